@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+// runExport implements the export subcommand, the CLI twin of GET /api/admin/export
+func runExport(cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "perweb-export.zip", "archive to write")
+	format := flags.String("format", model.ArchiveFormatJSON, "json or ndjson")
+	withHashes := flags.Bool("include-password-hashes", false, "export user password hashes")
+	withMedia := flags.Bool("include-media", false, "download referenced images and audio into the archive")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	archives, err := newArchiveService(cfg)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	opts := model.ExportOptions{Format: *format, IncludePasswordHashes: *withHashes, IncludeMedia: *withMedia}
+	if err := archives.Export(context.Background(), file, opts); err != nil {
+		return err
+	}
+	fmt.Println("exported to", *out)
+	return nil
+}
+
+// runImport implements the import subcommand, the CLI twin of POST /api/admin/import
+func runImport(cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import [--dry-run] <archive.zip>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	archives, err := newArchiveService(cfg)
+	if err != nil {
+		return err
+	}
+
+	report, err := archives.Import(context.Background(), file, info.Size(), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func newArchiveService(cfg config.Config) (service.ArchiveService, error) {
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewArchiveService(repository.NewArchiveRepository(db)), nil
+}
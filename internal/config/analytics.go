@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type AnalyticsConfig struct {
+	Enabled        bool          `mapstructure:"ANALYTICS_ENABLED"`
+	RollupInterval time.Duration `mapstructure:"ANALYTICS_ROLLUP_INTERVAL"`
+	RawRetention   time.Duration `mapstructure:"ANALYTICS_RAW_RETENTION"` // How long raw events outlive their day
+}
+
+func LoadAnalyticsConfig() (AnalyticsConfig, error) {
+	var config AnalyticsConfig
+
+	viper.SetDefault("ANALYTICS_ENABLED", true)
+	viper.SetDefault("ANALYTICS_ROLLUP_INTERVAL", time.Minute*10)
+	viper.SetDefault("ANALYTICS_RAW_RETENTION", time.Hour*24)
+
+	config.Enabled = viper.GetBool("ANALYTICS_ENABLED")
+	config.RollupInterval = viper.GetDuration("ANALYTICS_ROLLUP_INTERVAL")
+	config.RawRetention = viper.GetDuration("ANALYTICS_RAW_RETENTION")
+
+	return config, nil
+}
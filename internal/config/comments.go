@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type CommentConfig struct {
+	AutoApproveReturning bool          `mapstructure:"COMMENT_AUTO_APPROVE_RETURNING"` // Skip moderation for emails with an approved comment
+	MaxLinks             int           `mapstructure:"COMMENT_MAX_LINKS"`
+	Blocklist            []string      `mapstructure:"COMMENT_BLOCKLIST"`  // Lower case words that mark a comment as spam
+	RateLimit            int           `mapstructure:"COMMENT_RATE_LIMIT"` // Comments per address within RateWindow
+	RateWindow           time.Duration `mapstructure:"COMMENT_RATE_WINDOW"`
+	HashSecret           string        `mapstructure:"VISITOR_HASH_SECRET"`
+}
+
+func LoadCommentConfig() (CommentConfig, error) {
+	var config CommentConfig
+
+	viper.SetDefault("COMMENT_AUTO_APPROVE_RETURNING", false)
+	viper.SetDefault("COMMENT_MAX_LINKS", 2)
+	viper.SetDefault("COMMENT_BLOCKLIST", "")
+	viper.SetDefault("COMMENT_RATE_LIMIT", 5)
+	viper.SetDefault("COMMENT_RATE_WINDOW", time.Minute*10)
+
+	secret, err := visitorHashSecret()
+	if err != nil {
+		return config, err
+	}
+	config.HashSecret = secret
+	config.AutoApproveReturning = viper.GetBool("COMMENT_AUTO_APPROVE_RETURNING")
+	config.MaxLinks = viper.GetInt("COMMENT_MAX_LINKS")
+	config.RateLimit = viper.GetInt("COMMENT_RATE_LIMIT")
+	config.RateWindow = viper.GetDuration("COMMENT_RATE_WINDOW")
+	for _, word := range strings.Split(viper.GetString("COMMENT_BLOCKLIST"), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			config.Blocklist = append(config.Blocklist, word)
+		}
+	}
+
+	return config, nil
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -12,6 +14,14 @@ type Config struct {
 	DBName     string `mapstructure:"DB_NAME"`
 	DBSSLMode  string `mapstructure:"DB_SSLMODE"`
 	ServerPort string `mapstructure:"SERVER_PORT"`
+
+	LogLevel        string        `mapstructure:"LOG_LEVEL"`         // debug, info, warn or error
+	LogFormat       string        `mapstructure:"LOG_FORMAT"`        // json or text
+	DBSlowThreshold time.Duration `mapstructure:"DB_SLOW_THRESHOLD"` // Queries slower than this are logged as warnings; 0 turns that off
+
+	OTLPEndpoint     string  `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"` // Base URL of the OTLP/HTTP collector; empty turns tracing off
+	OTelServiceName  string  `mapstructure:"OTEL_SERVICE_NAME"`
+	TraceSampleRatio float64 `mapstructure:"OTEL_TRACES_SAMPLE_RATIO"` // Share of new traces kept; requests continuing a trace follow the caller's decision
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -21,6 +31,13 @@ func LoadConfig(path string) (config Config, err error) {
 
 	viper.AutomaticEnv()
 
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("DB_SLOW_THRESHOLD", time.Millisecond*200)
+	viper.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	viper.SetDefault("OTEL_SERVICE_NAME", "perwebbe")
+	viper.SetDefault("OTEL_TRACES_SAMPLE_RATIO", 1.0)
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
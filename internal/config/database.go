@@ -2,10 +2,11 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 
+	"github.com/lutestringamend/perwebbe/internal/logging"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func SetupDatabase(cfg Config) (*gorm.DB, error) {
@@ -13,7 +14,7 @@ func SetupDatabase(cfg Config) (*gorm.DB, error) {
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBSSLMode)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: logging.NewGormLogger(slog.Default(), cfg.DBSlowThreshold),
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type EventConfig struct {
+	PollInterval time.Duration `mapstructure:"EVENT_POLL_INTERVAL"` // 0 leaves events in the outbox
+	MaxAttempts  int           `mapstructure:"EVENT_MAX_ATTEMPTS"`
+	BackoffBase  time.Duration `mapstructure:"EVENT_BACKOFF_BASE"`
+	BackoffMax   time.Duration `mapstructure:"EVENT_BACKOFF_MAX"`
+	Retention    time.Duration `mapstructure:"EVENT_RETENTION"` // How long processed events are kept
+}
+
+func LoadEventConfig() (EventConfig, error) {
+	var config EventConfig
+
+	viper.SetDefault("EVENT_POLL_INTERVAL", time.Second)
+	viper.SetDefault("EVENT_MAX_ATTEMPTS", 10)
+	viper.SetDefault("EVENT_BACKOFF_BASE", time.Second*5)
+	viper.SetDefault("EVENT_BACKOFF_MAX", time.Hour)
+	viper.SetDefault("EVENT_RETENTION", time.Hour*24*7)
+
+	config.PollInterval = viper.GetDuration("EVENT_POLL_INTERVAL")
+	config.MaxAttempts = viper.GetInt("EVENT_MAX_ATTEMPTS")
+	config.BackoffBase = viper.GetDuration("EVENT_BACKOFF_BASE")
+	config.BackoffMax = viper.GetDuration("EVENT_BACKOFF_MAX")
+	config.Retention = viper.GetDuration("EVENT_RETENTION")
+
+	return config, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type GitProviderConfig struct {
+	GitHubHost      string        `mapstructure:"GITHUB_HOST"`
+	GitHubAPIURL    string        `mapstructure:"GITHUB_API_URL"`
+	GitHubToken     string        `mapstructure:"GITHUB_TOKEN"`
+	GitLabHost      string        `mapstructure:"GITLAB_HOST"`
+	GitLabAPIURL    string        `mapstructure:"GITLAB_API_URL"`
+	GitLabToken     string        `mapstructure:"GITLAB_TOKEN"`
+	RefreshInterval time.Duration `mapstructure:"GIT_REFRESH_INTERVAL"` // 0 disables the periodic refresh
+}
+
+func LoadGitProviderConfig() (GitProviderConfig, error) {
+	var config GitProviderConfig
+
+	viper.SetDefault("GITHUB_HOST", "github.com")
+	viper.SetDefault("GITHUB_API_URL", "https://api.github.com")
+	viper.SetDefault("GITLAB_HOST", "gitlab.com")
+	viper.SetDefault("GITLAB_API_URL", "https://gitlab.com/api/v4")
+	viper.SetDefault("GIT_REFRESH_INTERVAL", time.Hour*6)
+
+	config.GitHubHost = viper.GetString("GITHUB_HOST")
+	config.GitHubAPIURL = viper.GetString("GITHUB_API_URL")
+	config.GitHubToken = viper.GetString("GITHUB_TOKEN")
+	config.GitLabHost = viper.GetString("GITLAB_HOST")
+	config.GitLabAPIURL = viper.GetString("GITLAB_API_URL")
+	config.GitLabToken = viper.GetString("GITLAB_TOKEN")
+	config.RefreshInterval = viper.GetDuration("GIT_REFRESH_INTERVAL")
+
+	return config, nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type JobConfig struct {
+	Workers         int           `mapstructure:"JOB_WORKERS"` // 0 runs no jobs in this instance
+	PollInterval    time.Duration `mapstructure:"JOB_POLL_INTERVAL"`
+	MaxAttempts     int           `mapstructure:"JOB_MAX_ATTEMPTS"`
+	BackoffBase     time.Duration `mapstructure:"JOB_BACKOFF_BASE"`
+	BackoffMax      time.Duration `mapstructure:"JOB_BACKOFF_MAX"`
+	Timeout         time.Duration `mapstructure:"JOB_TIMEOUT"`
+	ShutdownTimeout time.Duration `mapstructure:"JOB_SHUTDOWN_TIMEOUT"` // How long running jobs may finish on shutdown
+	Retention       time.Duration `mapstructure:"JOB_RETENTION"`        // How long succeeded jobs are kept
+}
+
+func LoadJobConfig() (JobConfig, error) {
+	var config JobConfig
+
+	viper.SetDefault("JOB_WORKERS", 4)
+	viper.SetDefault("JOB_POLL_INTERVAL", time.Second)
+	viper.SetDefault("JOB_MAX_ATTEMPTS", 5)
+	viper.SetDefault("JOB_BACKOFF_BASE", time.Second*10)
+	viper.SetDefault("JOB_BACKOFF_MAX", time.Hour)
+	viper.SetDefault("JOB_TIMEOUT", time.Minute*15)
+	viper.SetDefault("JOB_SHUTDOWN_TIMEOUT", time.Second*30)
+	viper.SetDefault("JOB_RETENTION", time.Hour*24*7)
+
+	config.Workers = viper.GetInt("JOB_WORKERS")
+	config.PollInterval = viper.GetDuration("JOB_POLL_INTERVAL")
+	config.MaxAttempts = viper.GetInt("JOB_MAX_ATTEMPTS")
+	config.BackoffBase = viper.GetDuration("JOB_BACKOFF_BASE")
+	config.BackoffMax = viper.GetDuration("JOB_BACKOFF_MAX")
+	config.Timeout = viper.GetDuration("JOB_TIMEOUT")
+	config.ShutdownTimeout = viper.GetDuration("JOB_SHUTDOWN_TIMEOUT")
+	config.Retention = viper.GetDuration("JOB_RETENTION")
+
+	return config, nil
+}
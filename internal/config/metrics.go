@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"METRICS_ENABLED"`
+	Token   string `mapstructure:"METRICS_TOKEN"` // Bearer token required on /metrics; empty leaves it open
+}
+
+func LoadMetricsConfig() (MetricsConfig, error) {
+	var config MetricsConfig
+
+	viper.SetDefault("METRICS_ENABLED", true)
+	viper.SetDefault("METRICS_TOKEN", "")
+
+	config.Enabled = viper.GetBool("METRICS_ENABLED")
+	config.Token = viper.GetString("METRICS_TOKEN")
+
+	return config, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type MailConfig struct {
+	Driver       string `mapstructure:"MAIL_DRIVER"` // smtp or log
+	From         string `mapstructure:"MAIL_FROM"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+}
+
+type NewsletterConfig struct {
+	APIURL         string        `mapstructure:"NEWSLETTER_API_URL"`         // Public origin of this API, used for confirm and unsubscribe links
+	DigestInterval time.Duration `mapstructure:"NEWSLETTER_DIGEST_INTERVAL"` // 0 disables the digest job
+	MaxPostAge     time.Duration `mapstructure:"NEWSLETTER_MAX_POST_AGE"`    // Older posts are never sent, e.g. after an import
+	BatchSize      int           `mapstructure:"NEWSLETTER_BATCH_SIZE"`
+	BatchPause     time.Duration `mapstructure:"NEWSLETTER_BATCH_PAUSE"` // Wait between batches to respect provider limits
+}
+
+func LoadMailConfig() (MailConfig, error) {
+	var config MailConfig
+
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "noreply@localhost")
+	viper.SetDefault("SMTP_PORT", 587)
+
+	config.Driver = strings.ToLower(viper.GetString("MAIL_DRIVER"))
+	config.From = viper.GetString("MAIL_FROM")
+	config.SMTPHost = viper.GetString("SMTP_HOST")
+	config.SMTPPort = viper.GetInt("SMTP_PORT")
+	config.SMTPUsername = viper.GetString("SMTP_USERNAME")
+	config.SMTPPassword = viper.GetString("SMTP_PASSWORD")
+
+	return config, nil
+}
+
+func LoadNewsletterConfig() (NewsletterConfig, error) {
+	var config NewsletterConfig
+
+	viper.SetDefault("NEWSLETTER_DIGEST_INTERVAL", time.Hour)
+	viper.SetDefault("NEWSLETTER_MAX_POST_AGE", time.Hour*24*7)
+	viper.SetDefault("NEWSLETTER_BATCH_SIZE", 50)
+	viper.SetDefault("NEWSLETTER_BATCH_PAUSE", time.Minute)
+
+	config.APIURL = strings.TrimRight(viper.GetString("NEWSLETTER_API_URL"), "/")
+	if config.APIURL == "" {
+		config.APIURL = apiURL()
+	}
+	config.DigestInterval = viper.GetDuration("NEWSLETTER_DIGEST_INTERVAL")
+	config.MaxPostAge = viper.GetDuration("NEWSLETTER_MAX_POST_AGE")
+	config.BatchSize = viper.GetInt("NEWSLETTER_BATCH_SIZE")
+	config.BatchPause = viper.GetDuration("NEWSLETTER_BATCH_PAUSE")
+
+	return config, nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type ReactionConfig struct {
+	Emojis     []string `mapstructure:"REACTION_EMOJIS"` // Reactions visitors may send, in display order
+	HashSecret string   `mapstructure:"VISITOR_HASH_SECRET"`
+}
+
+func LoadReactionConfig() (ReactionConfig, error) {
+	var config ReactionConfig
+	var err error
+
+	viper.SetDefault("REACTION_EMOJIS", "👏,❤️,🔥,🎉,🤔")
+
+	config.HashSecret, err = visitorHashSecret()
+	if err != nil {
+		return config, err
+	}
+	for _, emoji := range strings.Split(viper.GetString("REACTION_EMOJIS"), ",") {
+		if emoji = strings.TrimSpace(emoji); emoji != "" {
+			config.Emojis = append(config.Emojis, emoji)
+		}
+	}
+
+	return config, nil
+}
+
+// visitorHashSecret reads VISITOR_HASH_SECRET, the key of the HMAC that stands
+// in for visitor addresses in reactions and comments
+func visitorHashSecret() (string, error) {
+	secret := viper.GetString("VISITOR_HASH_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("VISITOR_HASH_SECRET is required")
+	}
+	return secret, nil
+}
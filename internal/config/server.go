@@ -0,0 +1,36 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type ServerConfig struct {
+	ReadHeaderTimeout time.Duration `mapstructure:"SERVER_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`  // 0 for none, as uploads can be large
+	WriteTimeout      time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"` // 0 for none, as exports can be slow
+	IdleTimeout       time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ShutdownDelay     time.Duration `mapstructure:"SERVER_SHUTDOWN_DELAY"`   // How long /readyz fails before the server stops accepting requests
+	ShutdownTimeout   time.Duration `mapstructure:"SERVER_SHUTDOWN_TIMEOUT"` // How long in-flight requests may finish
+}
+
+func LoadServerConfig() (ServerConfig, error) {
+	var config ServerConfig
+
+	viper.SetDefault("SERVER_READ_HEADER_TIMEOUT", time.Second*10)
+	viper.SetDefault("SERVER_READ_TIMEOUT", 0)
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", 0)
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", time.Minute*2)
+	viper.SetDefault("SERVER_SHUTDOWN_DELAY", 0)
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", time.Second*30)
+
+	config.ReadHeaderTimeout = viper.GetDuration("SERVER_READ_HEADER_TIMEOUT")
+	config.ReadTimeout = viper.GetDuration("SERVER_READ_TIMEOUT")
+	config.WriteTimeout = viper.GetDuration("SERVER_WRITE_TIMEOUT")
+	config.IdleTimeout = viper.GetDuration("SERVER_IDLE_TIMEOUT")
+	config.ShutdownDelay = viper.GetDuration("SERVER_SHUTDOWN_DELAY")
+	config.ShutdownTimeout = viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT")
+
+	return config, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type SiteConfig struct {
+	URL             string   `mapstructure:"SITE_URL"` // Public origin of the frontend, used for absolute links
+	APIURL          string   `mapstructure:"API_URL"`  // Public origin of this API, where the sitemaps are served
+	Title           string   `mapstructure:"SITE_TITLE"`
+	BlogPath        string   `mapstructure:"SITE_BLOG_PATH"`      // Frontend route of a post, followed by its slug
+	PortfolioPath   string   `mapstructure:"SITE_PORTFOLIO_PATH"` // Frontend route of a project, followed by its ID
+	TagPath         string   `mapstructure:"SITE_TAG_PATH"`       // Frontend route of a tag page, followed by the tag name
+	RobotsDisallow  []string `mapstructure:"ROBOTS_DISALLOW"`
+	RobotsTxt       string   `mapstructure:"ROBOTS_TXT"` // Replaces the generated robots.txt when set
+	StaticExportDir string   `mapstructure:"STATIC_EXPORT_DIR"`
+}
+
+func LoadSiteConfig() (SiteConfig, error) {
+	var config SiteConfig
+
+	viper.SetDefault("SITE_URL", "http://localhost:3000")
+	viper.SetDefault("SITE_TITLE", "PERWEB")
+	viper.SetDefault("SITE_BLOG_PATH", "/blog")
+	viper.SetDefault("SITE_PORTFOLIO_PATH", "/portfolio")
+	viper.SetDefault("SITE_TAG_PATH", "/blog/tag")
+	viper.SetDefault("STATIC_EXPORT_DIR", "static")
+	viper.SetDefault("ROBOTS_DISALLOW", "/api/")
+
+	config.URL = strings.TrimRight(viper.GetString("SITE_URL"), "/")
+	config.APIURL = apiURL()
+	config.Title = viper.GetString("SITE_TITLE")
+	config.BlogPath = "/" + strings.Trim(viper.GetString("SITE_BLOG_PATH"), "/")
+	config.PortfolioPath = "/" + strings.Trim(viper.GetString("SITE_PORTFOLIO_PATH"), "/")
+	config.TagPath = "/" + strings.Trim(viper.GetString("SITE_TAG_PATH"), "/")
+	config.StaticExportDir = viper.GetString("STATIC_EXPORT_DIR")
+	config.RobotsTxt = viper.GetString("ROBOTS_TXT")
+	for _, path := range strings.Split(viper.GetString("ROBOTS_DISALLOW"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			config.RobotsDisallow = append(config.RobotsDisallow, path)
+		}
+	}
+
+	return config, nil
+}
+
+// apiURL reads API_URL, which NEWSLETTER_API_URL falls back to
+func apiURL() string {
+	viper.SetDefault("API_URL", "http://localhost:8080")
+	return strings.TrimRight(viper.GetString("API_URL"), "/")
+}
+
+// PostURL returns the public URL of a blog post
+func (c SiteConfig) PostURL(slug string) string {
+	return c.URL + c.BlogPath + "/" + slug
+}
+
+// ProjectURL returns the public URL of a portfolio project
+func (c SiteConfig) ProjectURL(id uint) string {
+	return fmt.Sprintf("%s%s/%d", c.URL, c.PortfolioPath, id)
+}
+
+// TagURL returns the public URL of a tag page
+func (c SiteConfig) TagURL(name string) string {
+	return c.URL + c.TagPath + "/" + url.PathEscape(name)
+}
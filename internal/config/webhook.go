@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type WebhookConfig struct {
+	PollInterval time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"` // 0 disables delivery
+	Timeout      time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	MaxAttempts  int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	BackoffBase  time.Duration `mapstructure:"WEBHOOK_BACKOFF_BASE"`
+	BackoffMax   time.Duration `mapstructure:"WEBHOOK_BACKOFF_MAX"`
+}
+
+func LoadWebhookConfig() (WebhookConfig, error) {
+	var config WebhookConfig
+
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", time.Second*5)
+	viper.SetDefault("WEBHOOK_TIMEOUT", time.Second*10)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_BACKOFF_BASE", time.Second*30)
+	viper.SetDefault("WEBHOOK_BACKOFF_MAX", time.Hour*6)
+
+	config.PollInterval = viper.GetDuration("WEBHOOK_POLL_INTERVAL")
+	config.Timeout = viper.GetDuration("WEBHOOK_TIMEOUT")
+	config.MaxAttempts = viper.GetInt("WEBHOOK_MAX_ATTEMPTS")
+	config.BackoffBase = viper.GetDuration("WEBHOOK_BACKOFF_BASE")
+	config.BackoffMax = viper.GetDuration("WEBHOOK_BACKOFF_MAX")
+
+	return config, nil
+}
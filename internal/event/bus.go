@@ -0,0 +1,201 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/backoff"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/lutestringamend/perwebbe/internal/event")
+
+const (
+	batchSize = 50
+	// lease keeps a claimed event away from other instances while its subscribers run
+	lease = time.Minute * 5
+)
+
+// Handler reacts to an event. A returned error makes the bus retry the event
+// for this subscriber later.
+type Handler func(ctx context.Context, event model.DomainEvent) error
+
+type subscription struct {
+	name    string
+	event   string // Empty for every event
+	handler Handler
+}
+
+// Bus hands the events stored in the outbox to the subscribers.
+//
+// Repositories write events in the same transaction as the change that caused
+// them, so an event is neither lost when the process dies after the commit nor
+// sent for a write that was rolled back. Every subscriber gets each event at
+// least once; one that succeeded is not called again when another one fails.
+type Bus struct {
+	repo          repository.OutboxRepository
+	cfg           config.EventConfig
+	subscriptions []subscription
+	wake          chan struct{}
+}
+
+func NewBus(repo repository.OutboxRepository, cfg config.EventConfig) *Bus {
+	return &Bus{repo: repo, cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// Subscribe calls handler for every event of type E. Subscriptions are made
+// at startup, before Run; name identifies the subscriber in the outbox.
+func Subscribe[E model.DomainEvent](b *Bus, name string, handler func(ctx context.Context, event E) error) {
+	var zero E
+	b.subscriptions = append(b.subscriptions, subscription{
+		name:  name,
+		event: zero.EventName(),
+		handler: func(ctx context.Context, event model.DomainEvent) error {
+			return handler(ctx, event.(E))
+		},
+	})
+}
+
+// SubscribeAll calls handler for every event
+func (b *Bus) SubscribeAll(name string, handler Handler) {
+	b.subscriptions = append(b.subscriptions, subscription{name: name, handler: handler})
+}
+
+// Wake makes Run dispatch right away instead of at its next tick.
+// Services call it after a write that stored events.
+func (b *Bus) Wake() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Publish stores events in the outbox and wakes Run. Events caused by a write
+// are stored by the repository instead, in the transaction of that write.
+func (b *Bus) Publish(ctx context.Context, events ...model.DomainEvent) error {
+	if err := b.repo.Create(ctx, events...); err != nil {
+		return err
+	}
+	b.Wake()
+	return nil
+}
+
+// Run dispatches stored events every interval, or when woken, until ctx is cancelled
+func (b *Bus) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if b.cfg.Retention > 0 {
+				if _, err := b.repo.DeleteProcessed(ctx, time.Now().Add(-b.cfg.Retention)); err != nil {
+					slog.ErrorContext(ctx, "processed events were not cleaned up", "error", err)
+				}
+			}
+		case <-b.wake:
+		}
+
+		if err := b.Dispatch(ctx); err != nil {
+			slog.ErrorContext(ctx, "event dispatch failed", "error", err)
+		}
+	}
+}
+
+// Dispatch passes every due event to its subscribers, a batch at a time
+func (b *Bus) Dispatch(ctx context.Context) error {
+	for ctx.Err() == nil {
+		events, err := b.repo.ClaimDue(ctx, time.Now(), lease, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			b.dispatch(ctx, &events[i])
+			// The outcome is saved even when ctx was cancelled during the dispatch
+			if err := b.repo.Update(context.WithoutCancel(ctx), &events[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// dispatch runs the subscribers that have not handled the event yet and
+// records the outcome, scheduling a retry with exponential backoff until the
+// attempts are used up
+func (b *Bus) dispatch(ctx context.Context, stored *model.OutboxEvent) {
+	now := time.Now()
+	stored.Attempts++
+	stored.Error = ""
+
+	ctx, span := tracer.Start(ctx, "event "+stored.Event, trace.WithAttributes(
+		attribute.Int64("event.id", int64(stored.ID)),
+		attribute.Int("event.attempt", stored.Attempts),
+	))
+	defer func() {
+		if stored.Error != "" {
+			span.SetStatus(codes.Error, stored.Error)
+		}
+		span.End()
+	}()
+
+	event, err := Decode(stored.Event, []byte(stored.Payload))
+	if err != nil {
+		stored.Status = model.OutboxFailed
+		stored.Error = err.Error()
+		return
+	}
+
+	var failures []string
+	for _, sub := range b.subscriptions {
+		if (sub.event != "" && sub.event != stored.Event) || slices.Contains(stored.Handled, sub.name) {
+			continue
+		}
+		if err := b.call(ctx, sub, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+			continue
+		}
+		stored.Handled = append(stored.Handled, sub.name)
+	}
+
+	if len(failures) == 0 {
+		stored.Status = model.OutboxProcessed
+		stored.ProcessedAt = &now
+		return
+	}
+
+	stored.Error = strings.Join(failures, "; ")
+	if stored.Attempts >= b.cfg.MaxAttempts {
+		stored.Status = model.OutboxFailed
+		slog.ErrorContext(ctx, "event failed for good", "event_id", stored.ID, "event", stored.Event, "error", stored.Error)
+		return
+	}
+	stored.NextAttemptAt = now.Add(backoff.Exponential(b.cfg.BackoffBase, b.cfg.BackoffMax, stored.Attempts))
+}
+
+// call runs one subscriber, turning a panic into an error so the others still run
+func (b *Bus) call(ctx context.Context, sub subscription, event model.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
@@ -0,0 +1,92 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+)
+
+type BlogCreated struct {
+	Post *model.BlogPost `json:"post"`
+}
+
+func (BlogCreated) EventName() string { return model.WebhookEventBlogCreated }
+
+// BlogPublished follows BlogCreated or BlogUpdated when a post becomes visible,
+// or is published on its own once the PublishAt of a scheduled post has come
+type BlogPublished struct {
+	Post *model.BlogPost `json:"post"`
+}
+
+func (BlogPublished) EventName() string { return model.WebhookEventBlogPublished }
+
+type BlogUpdated struct {
+	Post *model.BlogPost `json:"post"`
+}
+
+func (BlogUpdated) EventName() string { return model.WebhookEventBlogUpdated }
+
+// BlogDeleted carries the post as it was before the delete
+type BlogDeleted struct {
+	Post *model.BlogPost `json:"post"`
+}
+
+func (BlogDeleted) EventName() string { return model.WebhookEventBlogDeleted }
+
+type ProjectCreated struct {
+	Project *model.PortfolioProject `json:"project"`
+}
+
+func (ProjectCreated) EventName() string { return model.WebhookEventProjectCreated }
+
+type ProjectUpdated struct {
+	Project *model.PortfolioProject `json:"project"`
+}
+
+func (ProjectUpdated) EventName() string { return model.WebhookEventProjectUpdated }
+
+// ProjectDeleted carries the project as it was before the delete
+type ProjectDeleted struct {
+	Project *model.PortfolioProject `json:"project"`
+}
+
+func (ProjectDeleted) EventName() string { return model.WebhookEventProjectDeleted }
+
+type ContactReceived struct {
+	Submission *model.ContactSubmission `json:"submission"`
+}
+
+func (ContactReceived) EventName() string { return model.WebhookEventContactReceived }
+
+// decoders turn outbox payloads back into typed events
+var decoders = map[string]func(payload []byte) (model.DomainEvent, error){}
+
+func register[E model.DomainEvent]() {
+	var zero E
+	decoders[zero.EventName()] = func(payload []byte) (model.DomainEvent, error) {
+		var event E
+		err := json.Unmarshal(payload, &event)
+		return event, err
+	}
+}
+
+func init() {
+	register[BlogCreated]()
+	register[BlogPublished]()
+	register[BlogUpdated]()
+	register[BlogDeleted]()
+	register[ProjectCreated]()
+	register[ProjectUpdated]()
+	register[ProjectDeleted]()
+	register[ContactReceived]()
+}
+
+// Decode rebuilds a typed event from its name and stored payload
+func Decode(name string, payload []byte) (model.DomainEvent, error) {
+	decode, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decode(payload)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type AnalyticsHandler struct {
+	service service.AnalyticsService
+}
+
+func NewAnalyticsHandler(service service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// RecordView answers 204 whether or not the view was counted, so clients
+// cannot tell which requests were filtered out
+func (h *AnalyticsHandler) RecordView(c *gin.Context) {
+	var req model.PageViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Visitors who opted out of tracking are not recorded at all
+	if c.GetHeader("DNT") == "1" || c.GetHeader("Sec-GPC") == "1" {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := h.service.RecordView(c.Request.Context(), req, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AnalyticsHandler) GetTopContent(c *gin.Context) {
+	q, ok := analyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.TopContent(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *AnalyticsHandler) GetTimeSeries(c *gin.Context) {
+	q, ok := analyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.service.TimeSeries(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+func (h *AnalyticsHandler) GetReferrers(c *gin.Context) {
+	q, ok := analyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.Referrers(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Rollup refreshes the daily tables right away instead of waiting for the next interval
+func (h *AnalyticsHandler) Rollup(c *gin.Context) {
+	if err := h.service.Rollup(c.Request.Context()); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "analytics rolled up successfully"})
+}
+
+// analyticsQuery parses type, key, event, from, to (YYYY-MM-DD), limit and by=utm
+func analyticsQuery(c *gin.Context) (model.AnalyticsQuery, bool) {
+	q := model.AnalyticsQuery{
+		ContentType: c.Query("type"),
+		ContentKey:  c.Query("key"),
+		Event:       c.Query("event"),
+		ByUTM:       c.Query("by") == "utm",
+	}
+	q.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	for param, target := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + " date, expected YYYY-MM-DD"})
+			return q, false
+		}
+		*target = day
+	}
+	return q, true
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type ArchiveHandler struct {
+	service service.ArchiveService
+}
+
+func NewArchiveHandler(service service.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{service: service}
+}
+
+func (h *ArchiveHandler) Export(c *gin.Context) {
+	opts := model.ExportOptions{Format: c.DefaultQuery("format", model.ArchiveFormatJSON)}
+	opts.IncludePasswordHashes, _ = strconv.ParseBool(c.Query("include_password_hashes"))
+	opts.IncludeMedia, _ = strconv.ParseBool(c.Query("include_media"))
+
+	if opts.Format != model.ArchiveFormatJSON && opts.Format != model.ArchiveFormatNDJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or ndjson"})
+		return
+	}
+
+	filename := fmt.Sprintf("perweb-export-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// The archive is streamed, so a failure halfway can only cut the download short
+	if err := h.service.Export(c.Request.Context(), c.Writer, opts); err != nil {
+		slog.ErrorContext(c.Request.Context(), "export failed", "error", err)
+		c.Abort()
+	}
+}
+
+func (h *ArchiveHandler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archive file is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(c.Request.Context(), file, fileHeader.Size, dryRun)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
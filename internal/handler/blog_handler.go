@@ -24,7 +24,9 @@ func (h *BlogHandler) CreateBlog(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateBlog(&blog); err != nil {
+	// Series membership is set through the series endpoints
+	blog.SeriesID, blog.SeriesPosition = nil, 0
+	if err := h.service.CreateBlog(c.Request.Context(), &blog); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -39,7 +41,7 @@ func (h *BlogHandler) GetBlog(c *gin.Context) {
 		return
 	}
 
-	blog, err := h.service.GetBlogByID(uint(id))
+	blog, err := h.service.GetBlogByID(c.Request.Context(), uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -59,7 +61,7 @@ func (h *BlogHandler) GetBlogBySlug(c *gin.Context) {
 		return
 	}
 
-	blog, err := h.service.GetBlogBySlug(slug)
+	blog, err := h.service.GetBlogBySlug(c.Request.Context(), slug)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -86,9 +88,9 @@ func (h *BlogHandler) GetAllBlogs(c *gin.Context) {
 		pageSize = 100
 	}
 
-	paginator, err := h.service.GetAllBlogs(page, pageSize)
+	paginator, err := h.service.GetAllBlogs(c.Request.Context(), page, pageSize, c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -102,7 +104,7 @@ func (h *BlogHandler) UpdateBlog(c *gin.Context) {
 		return
 	}
 
-	blog, err := h.service.GetBlogByID(uint(id))
+	blog, err := h.service.GetBlogByID(c.Request.Context(), uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -120,7 +122,9 @@ func (h *BlogHandler) UpdateBlog(c *gin.Context) {
 	}
 
 	updatedBlog.ID = blog.ID
-	if err := h.service.UpdateBlog(&updatedBlog); err != nil {
+	updatedBlog.SeriesID = blog.SeriesID
+	updatedBlog.SeriesPosition = blog.SeriesPosition
+	if err := h.service.UpdateBlog(c.Request.Context(), &updatedBlog); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -128,6 +132,28 @@ func (h *BlogHandler) UpdateBlog(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedBlog)
 }
 
+// SetRelatedPins pins the posts in post_ids, in that order, before the computed related posts
+func (h *BlogHandler) SetRelatedPins(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	var req model.RelatedPinsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetRelatedPins(c.Request.Context(), uint(id), req.PostIDs); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "related posts updated successfully"})
+}
+
 func (h *BlogHandler) DeleteBlog(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -135,7 +161,7 @@ func (h *BlogHandler) DeleteBlog(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteBlog(uint(id)); err != nil {
+	if err := h.service.DeleteBlog(c.Request.Context(), uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/middleware"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type CommentHandler struct {
+	service service.CommentService
+}
+
+func NewCommentHandler(service service.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	thread, err := h.service.GetThread(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if thread == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+// CreateComment accepts guest comments as well as comments of logged in users;
+// the response only tells whether the comment is visible yet
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req model.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var userID *uint
+	if id, err := middleware.ExtractUserIDFromToken(c); err == nil {
+		userID = &id
+	}
+
+	comment, err := h.service.Submit(c.Request.Context(), c.Param("slug"), req, userID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if comment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
+		return
+	}
+
+	// Spam is reported as pending so spammers learn nothing about the checks
+	status := comment.Status
+	if status == model.CommentStatusSpam {
+		status = model.CommentStatusPending
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": comment.ID, "status": status})
+}
+
+// GetModerationQueue lists comments for admins, filtered by ?status= and ?post_id=
+func (h *CommentHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	filter := model.CommentFilter{Status: c.Query("status")}
+	if postID := c.Query("post_id"); postID != "" {
+		id, err := strconv.ParseUint(postID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post_id"})
+			return
+		}
+		filter.PostID = uint(id)
+	}
+
+	paginator, err := h.service.GetComments(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, paginator)
+}
+
+func (h *CommentHandler) ModerateComments(c *gin.Context) {
+	var req model.ModerateCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.ModerateComments(c.Request.Context(), req.IDs, req.Status)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	if err := h.service.DeleteComment(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
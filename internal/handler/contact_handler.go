@@ -24,7 +24,7 @@ func (h *ContactHandler) CreateContact(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateContact(&submission); err != nil {
+	if err := h.service.CreateContact(c.Request.Context(), &submission); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -46,7 +46,7 @@ func (h *ContactHandler) GetAllContacts(c *gin.Context) {
 		pageSize = 100
 	}
 
-	paginator, err := h.service.GetAllContacts(page, pageSize)
+	paginator, err := h.service.GetAllContacts(c.Request.Context(), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -62,7 +62,7 @@ func (h *ContactHandler) MarkContactAsRead(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.MarkContactAsRead(uint(id)); err != nil {
+	if err := h.service.MarkContactAsRead(c.Request.Context(), uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -77,7 +77,7 @@ func (h *ContactHandler) DeleteContact(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteContact(uint(id)); err != nil {
+	if err := h.service.DeleteContact(c.Request.Context(), uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
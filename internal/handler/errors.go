@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+// errorStatus maps an error returned by a service onto an HTTP status code
+func errorStatus(err error) int {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type HealthHandler struct {
+	service service.HealthService
+}
+
+func NewHealthHandler(service service.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Live answers as long as the process serves requests; it checks nothing else,
+// so a database outage does not get the container restarted
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": model.HealthOK})
+}
+
+// Ready answers 503 while a check fails or the server is shutting down
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.service.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != model.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
+
+func (h *HealthHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Version())
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type MarkdownImportHandler struct {
+	service service.MarkdownImportService
+}
+
+func NewMarkdownImportHandler(service service.MarkdownImportService) *MarkdownImportHandler {
+	return &MarkdownImportHandler{service: service}
+}
+
+// Import accepts either a zip upload in "archive" or one or more Markdown files in "files"
+func (h *MarkdownImportHandler) Import(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart form is required"})
+		return
+	}
+
+	var files []model.MarkdownFile
+	if archives := form.File["archive"]; len(archives) > 0 {
+		archive, err := archives[0].Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer archive.Close()
+
+		files, err = service.ReadMarkdownZip(archive, archives[0].Size)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		for _, fileHeader := range form.File["files"] {
+			file, err := fileHeader.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			content, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			files = append(files, model.MarkdownFile{Name: fileHeader.Filename, Content: content})
+		}
+	}
+
+	report, err := h.service.Import(c.Request.Context(), files, dryRun)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type MetricsHandler struct {
+	token   string
+	handler http.Handler
+}
+
+func NewMetricsHandler(token string) *MetricsHandler {
+	return &MetricsHandler{
+		token:   token,
+		handler: promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}),
+	}
+}
+
+// Serve writes the metrics in the Prometheus text format. When a token is
+// configured the scraper must send it as "Authorization: Bearer <token>".
+func (h *MetricsHandler) Serve(c *gin.Context) {
+	if h.token != "" {
+		expected := "Bearer " + h.token
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid metrics token"})
+			return
+		}
+	}
+
+	h.handler.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type NewsletterHandler struct {
+	service service.NewsletterService
+}
+
+func NewNewsletterHandler(service service.NewsletterService) *NewsletterHandler {
+	return &NewsletterHandler{service: service}
+}
+
+func (h *NewsletterHandler) Subscribe(c *gin.Context) {
+	var req model.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Subscribe(c.Request.Context(), req.Email); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "check your inbox to confirm the subscription"})
+}
+
+func (h *NewsletterHandler) Confirm(c *gin.Context) {
+	confirmed, err := h.service.Confirm(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !confirmed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid or expired token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "subscription confirmed"})
+}
+
+// unsubscribePage asks for a confirmation before anything changes, so link
+// scanners and prefetching mail clients that follow the link do not unsubscribe
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Unsubscribe</title></head>
+<body style="font-family:sans-serif;max-width:32rem;margin:4rem auto;padding:0 1rem">
+{{if .Done}}<p>You have been unsubscribed and will not receive any more emails.</p>
+{{else}}<p>Stop receiving emails from this newsletter?</p>
+<form method="post" action="?token={{.Token}}"><button type="submit">Unsubscribe</button></form>
+{{end}}</body>
+</html>
+`))
+
+// ConfirmUnsubscribe serves the link in the email with a page whose form posts
+// to Unsubscribe
+func (h *NewsletterHandler) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid token"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	unsubscribePage.Execute(c.Writer, gin.H{"Token": token})
+}
+
+// Unsubscribe serves the confirmation form and one-click unsubscribe requests
+// from mail clients (RFC 8058); browsers get a page back, everything else JSON
+func (h *NewsletterHandler) Unsubscribe(c *gin.Context) {
+	unsubscribed, err := h.service.Unsubscribe(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !unsubscribed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid token"})
+		return
+	}
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Status(http.StatusOK)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		unsubscribePage.Execute(c.Writer, gin.H{"Done": true})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "unsubscribed successfully"})
+}
+
+func (h *NewsletterHandler) GetSubscribers(c *gin.Context) {
+	page, pageSize := newsletterPaging(c)
+
+	paginator, err := h.service.GetSubscribers(c.Request.Context(), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, paginator)
+}
+
+func (h *NewsletterHandler) AddSubscriber(c *gin.Context) {
+	var req model.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscriber, err := h.service.AddSubscriber(c.Request.Context(), req.Email)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscriber)
+}
+
+func (h *NewsletterHandler) DeleteSubscriber(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	if err := h.service.DeleteSubscriber(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "subscriber deleted successfully"})
+}
+
+func (h *NewsletterHandler) GetIssues(c *gin.Context) {
+	page, pageSize := newsletterPaging(c)
+
+	paginator, err := h.service.GetIssues(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, paginator)
+}
+
+// SendDigest starts sending a digest of the new posts in the background
+func (h *NewsletterHandler) SendDigest(c *gin.Context) {
+	if err := h.service.TriggerDigest(c.Request.Context()); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "digest sending started"})
+}
+
+func newsletterPaging(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lutestringamend/perwebbe/internal/model"
@@ -24,8 +25,8 @@ func (h *PortfolioHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateProject(&project); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.CreateProject(c.Request.Context(), &project); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -39,7 +40,7 @@ func (h *PortfolioHandler) GetProject(c *gin.Context) {
 		return
 	}
 
-	blog, err := h.service.GetProjectByID(uint(id))
+	blog, err := h.service.GetProjectByID(c.Request.Context(), uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -55,7 +56,26 @@ func (h *PortfolioHandler) GetProject(c *gin.Context) {
 func (h *PortfolioHandler) GetAllProjects(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-	projectType := c.Query("type")
+	filter := model.ProjectFilter{ProjectType: c.Query("type"), Sort: c.Query("sort")}
+	if tech := c.Query("tech"); tech != "" {
+		filter.Technologies = strings.Split(tech, ",")
+	}
+	if featured := c.Query("featured"); featured != "" {
+		value, err := strconv.ParseBool(featured)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid featured value"})
+			return
+		}
+		filter.Featured = &value
+	}
+	if order := c.Query("order"); order != "" {
+		if order != "asc" && order != "desc" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+			return
+		}
+		descending := order == "desc"
+		filter.Descending = &descending
+	}
 
 	if page < 1 {
 		page = 1
@@ -67,9 +87,9 @@ func (h *PortfolioHandler) GetAllProjects(c *gin.Context) {
 		pageSize = 100
 	}
 
-	paginator, err := h.service.GetAllProjects(projectType, page, pageSize)
+	paginator, err := h.service.GetAllProjects(c.Request.Context(), filter, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -83,7 +103,7 @@ func (h *PortfolioHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.service.GetProjectByID(uint(id))
+	project, err := h.service.GetProjectByID(c.Request.Context(), uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -101,14 +121,35 @@ func (h *PortfolioHandler) UpdateProject(c *gin.Context) {
 	}
 
 	updatedProject.ID = project.ID
-	if err := h.service.UpdateProject(&updatedProject); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// The manual position is owned by the reorder endpoint and the
+	// repository stats by the git host sync
+	updatedProject.SortOrder = project.SortOrder
+	updatedProject.Stars = project.Stars
+	updatedProject.LastCommitAt = project.LastCommitAt
+	updatedProject.RepoSyncedAt = project.RepoSyncedAt
+	if err := h.service.UpdateProject(c.Request.Context(), &updatedProject); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, updatedProject)
 }
 
+func (h *PortfolioHandler) ReorderProjects(c *gin.Context) {
+	var request model.ProjectOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ReorderProjects(c.Request.Context(), request.IDs); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "portfolio projects reordered successfully"})
+}
+
 func (h *PortfolioHandler) DeleteProject(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -116,7 +157,7 @@ func (h *PortfolioHandler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteProject(uint(id)); err != nil {
+	if err := h.service.DeleteProject(c.Request.Context(), uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+	"github.com/lutestringamend/perwebbe/pkg/githost"
+)
+
+type ProjectImportHandler struct {
+	service service.ProjectImportService
+}
+
+func NewProjectImportHandler(service service.ProjectImportService) *ProjectImportHandler {
+	return &ProjectImportHandler{service: service}
+}
+
+func (h *ProjectImportHandler) ImportProject(c *gin.Context) {
+	var request model.ProjectImportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, repo, err := h.service.DraftFromRepository(c.Request.Context(), request.RepoURL)
+	if err != nil {
+		c.JSON(gitHostErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project, "repository": repo})
+}
+
+func (h *ProjectImportHandler) RefreshRepositories(c *gin.Context) {
+	refreshed, err := h.service.RefreshRepositoryStats(c.Request.Context())
+	if err != nil {
+		c.JSON(gitHostErrorStatus(err), gin.H{"error": err.Error(), "refreshed": refreshed})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"refreshed": refreshed})
+}
+
+// gitHostErrorStatus reports failures of the upstream git host as 502 rather than 500
+func gitHostErrorStatus(err error) int {
+	if errors.Is(err, githost.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	if status := errorStatus(err); status != http.StatusInternalServerError {
+		return status
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type ProjectTypeHandler struct {
+	service service.ProjectTypeService
+}
+
+func NewProjectTypeHandler(service service.ProjectTypeService) *ProjectTypeHandler {
+	return &ProjectTypeHandler{service: service}
+}
+
+func (h *ProjectTypeHandler) GetAllProjectTypes(c *gin.Context) {
+	projectTypes, err := h.service.GetAllProjectTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, projectTypes)
+}
+
+func (h *ProjectTypeHandler) CreateProjectType(c *gin.Context) {
+	var projectType model.ProjectType
+	if err := c.ShouldBindJSON(&projectType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateProjectType(c.Request.Context(), &projectType); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, projectType)
+}
+
+func (h *ProjectTypeHandler) UpdateProjectType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	projectType, err := h.service.GetProjectTypeByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if projectType == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project type not found"})
+		return
+	}
+
+	var updatedProjectType model.ProjectType
+	if err := c.ShouldBindJSON(&updatedProjectType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedProjectType.ID = projectType.ID
+	if err := h.service.UpdateProjectType(c.Request.Context(), &updatedProjectType); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedProjectType)
+}
+
+func (h *ProjectTypeHandler) DeleteProjectType(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	if err := h.service.DeleteProjectType(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "project type deleted successfully"})
+}
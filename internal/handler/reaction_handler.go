@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type ReactionHandler struct {
+	service service.ReactionService
+}
+
+func NewReactionHandler(service service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{service: service}
+}
+
+func (h *ReactionHandler) GetEmojis(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"emojis": h.service.Emojis()})
+}
+
+func (h *ReactionHandler) ReactToPost(c *gin.Context) {
+	var req model.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.service.ReactToPost(c.Request.Context(), c.Param("slug"), req.Emoji, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if summary == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func (h *ReactionHandler) ReactToProject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	var req model.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.service.ReactToProject(c.Request.Context(), uint(id), req.Emoji, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if summary == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "portfolio project not found"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type SitemapHandler struct {
+	service service.SitemapService
+}
+
+func NewSitemapHandler(service service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{service: service}
+}
+
+func (h *SitemapHandler) GetIndex(c *gin.Context) {
+	index, err := h.service.Index(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeXML(c, index)
+}
+
+// GetSitemap serves /sitemaps/<kind>.xml, with ?page=N for types split over several files
+func (h *SitemapHandler) GetSitemap(c *gin.Context) {
+	kind := strings.TrimSuffix(c.Param("file"), ".xml")
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+		return
+	}
+
+	urlSet, err := h.service.Sitemap(c.Request.Context(), kind, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if urlSet == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+	writeXML(c, urlSet)
+}
+
+func (h *SitemapHandler) GetRobotsTxt(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, h.service.RobotsTxt())
+}
+
+func writeXML(c *gin.Context, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type SocialCardHandler struct {
+	service service.SocialCardService
+}
+
+func NewSocialCardHandler(service service.SocialCardService) *SocialCardHandler {
+	return &SocialCardHandler{service: service}
+}
+
+func (h *SocialCardHandler) GetPostCard(c *gin.Context) {
+	card, err := h.service.PostCard(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if card == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog post not found"})
+		return
+	}
+	writeCard(c, card)
+}
+
+func (h *SocialCardHandler) GetProjectCard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	card, err := h.service.ProjectCard(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if card == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "portfolio project not found"})
+		return
+	}
+	writeCard(c, card)
+}
+
+func writeCard(c *gin.Context, card *model.SocialCard) {
+	if card.RedirectURL != "" {
+		c.Redirect(http.StatusFound, card.RedirectURL)
+		return
+	}
+
+	etag := `"` + card.ETag + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", card.PNG)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type StaticExportHandler struct {
+	service service.StaticExportService
+}
+
+func NewStaticExportHandler(service service.StaticExportService) *StaticExportHandler {
+	return &StaticExportHandler{service: service}
+}
+
+func (h *StaticExportHandler) Generate(c *gin.Context) {
+	full, _ := strconv.ParseBool(c.Query("full"))
+
+	report, err := h.service.Generate(c.Request.Context(), full)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+type TechnologyHandler struct {
+	service service.TechnologyService
+}
+
+func NewTechnologyHandler(service service.TechnologyService) *TechnologyHandler {
+	return &TechnologyHandler{service: service}
+}
+
+func (h *TechnologyHandler) GetAllTechnologies(c *gin.Context) {
+	technologies, err := h.service.GetAllTechnologies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, technologies)
+}
+
+func (h *TechnologyHandler) CreateTechnology(c *gin.Context) {
+	var technology model.Technology
+	if err := c.ShouldBindJSON(&technology); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateTechnology(c.Request.Context(), &technology); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, technology)
+}
+
+func (h *TechnologyHandler) UpdateTechnology(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	technology, err := h.service.GetTechnologyByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if technology == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "technology not found"})
+		return
+	}
+
+	var updatedTechnology model.Technology
+	if err := c.ShouldBindJSON(&updatedTechnology); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedTechnology.ID = technology.ID
+	if err := h.service.UpdateTechnology(c.Request.Context(), &updatedTechnology); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedTechnology)
+}
+
+func (h *TechnologyHandler) DeleteTechnology(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID format"})
+		return
+	}
+
+	if err := h.service.DeleteTechnology(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "technology deleted successfully"})
+}
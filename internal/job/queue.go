@@ -0,0 +1,314 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/backoff"
+	"github.com/lutestringamend/perwebbe/pkg/cron"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/lutestringamend/perwebbe/internal/job")
+
+// pruneInterval is how often succeeded jobs past the retention are deleted
+const pruneInterval = time.Hour
+
+// Handler does the work of a job. A returned error makes the queue retry the
+// job with backoff until its attempts are used up.
+type Handler func(ctx context.Context, job *model.Job) error
+
+// Option changes a job before it is queued
+type Option func(job *model.Job)
+
+// RunAt delays a job until t
+func RunAt(t time.Time) Option {
+	return func(job *model.Job) {
+		job.RunAt = t
+	}
+}
+
+// MaxAttempts overrides JOB_MAX_ATTEMPTS for one job
+func MaxAttempts(n int) Option {
+	return func(job *model.Job) {
+		job.MaxAttempts = n
+	}
+}
+
+type recurring struct {
+	kind     string
+	payload  string
+	schedule cron.Schedule
+}
+
+// Queue runs jobs stored in PostgreSQL with a pool of workers. Any number of
+// instances can share the table: jobs are claimed with SELECT ... FOR UPDATE
+// SKIP LOCKED, and a job whose worker died is taken over once its lease runs out.
+type Queue struct {
+	repo      repository.JobRepository
+	cfg       config.JobConfig
+	handlers  map[string]Handler
+	schedules map[string]recurring
+	wake      chan struct{}
+}
+
+func NewQueue(repo repository.JobRepository, cfg config.JobConfig) *Queue {
+	return &Queue{
+		repo:      repo,
+		cfg:       cfg,
+		handlers:  make(map[string]Handler),
+		schedules: make(map[string]recurring),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler of a job kind. Handlers are registered at startup,
+// before Run; this instance only claims jobs of registered kinds.
+func (q *Queue) Register(kind string, handler Handler) {
+	q.handlers[kind] = handler
+}
+
+// Handle registers a handler that receives the job payload decoded into P
+func Handle[P any](q *Queue, kind string, handler func(ctx context.Context, payload P) error) {
+	q.Register(kind, func(ctx context.Context, job *model.Job) error {
+		var payload P
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// Enqueue stores a job with its payload encoded as JSON
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload interface{}, opts ...Option) (*model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.Job{
+		Kind:        kind,
+		Payload:     string(data),
+		Status:      model.JobPending,
+		MaxAttempts: q.cfg.MaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	if err := q.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	q.Wake()
+	return job, nil
+}
+
+// Schedule queues a job of kind with payload whenever spec is due, see
+// cron.Parse. Times are in UTC. A run is skipped while the previous one has not
+// finished. Schedules are added at startup, before Run.
+func (q *Queue) Schedule(ctx context.Context, name, spec, kind string, payload interface{}) error {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	next := schedule.Next(time.Now().UTC())
+	if next.IsZero() {
+		return fmt.Errorf("schedule %q never runs", spec)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	record := &model.JobSchedule{Name: name, Spec: spec, Kind: kind, NextRunAt: next}
+	if err := q.repo.SaveSchedule(ctx, record); err != nil {
+		return err
+	}
+
+	q.schedules[name] = recurring{kind: kind, payload: string(data), schedule: schedule}
+	return nil
+}
+
+// Wake makes an idle worker look for jobs right away
+func (q *Queue) Wake() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run starts the workers and the scheduler and blocks until ctx is cancelled.
+// Running jobs then get ShutdownTimeout to finish before their context is
+// cancelled too; Run returns once every worker has stopped.
+func (q *Queue) Run(ctx context.Context) {
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, jobCtx, kinds)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.runScheduler(ctx)
+	}()
+
+	<-ctx.Done()
+	timer := time.AfterFunc(q.cfg.ShutdownTimeout, cancelJobs)
+	defer timer.Stop()
+	wg.Wait()
+}
+
+// work claims and runs jobs one at a time until ctx is cancelled
+func (q *Queue) work(ctx, jobCtx context.Context, kinds []string) {
+	// The lease outlasts the timeout, so a job is only taken over when its worker is gone
+	lease := q.cfg.Timeout + time.Minute
+
+	for ctx.Err() == nil {
+		job, err := q.repo.ClaimNext(ctx, kinds, time.Now(), lease)
+		if err != nil {
+			slog.ErrorContext(ctx, "job claim failed", "error", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-q.wake:
+			case <-time.After(q.cfg.PollInterval):
+			}
+			continue
+		}
+
+		q.run(jobCtx, job)
+	}
+}
+
+// run calls the handler and records the outcome, scheduling a retry with
+// exponential backoff or moving the job to the dead letters
+func (q *Queue) run(ctx context.Context, job *model.Job) {
+	ctx, cancel := context.WithTimeout(ctx, q.cfg.Timeout)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "job "+job.Kind, trace.WithAttributes(
+		attribute.Int64("job.id", int64(job.ID)),
+		attribute.Int("job.attempt", job.Attempts),
+	))
+	defer span.End()
+
+	err := q.call(ctx, job)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	now := time.Now()
+	job.LockedUntil = nil
+
+	switch {
+	case err == nil:
+		job.Status = model.JobSucceeded
+		job.FinishedAt = &now
+		job.LastError = ""
+	case job.Attempts >= job.MaxAttempts:
+		job.Status = model.JobDead
+		job.FinishedAt = &now
+		job.LastError = err.Error()
+		slog.ErrorContext(ctx, "job failed for good", "job_id", job.ID, "kind", job.Kind, "error", err)
+	default:
+		job.Status = model.JobPending
+		job.RunAt = now.Add(backoff.Exponential(q.cfg.BackoffBase, q.cfg.BackoffMax, job.Attempts))
+		job.LastError = err.Error()
+	}
+
+	// The outcome is saved even when the job was cancelled at shutdown
+	if err := q.repo.Update(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "job outcome was not saved", "job_id", job.ID, "kind", job.Kind, "error", err)
+	}
+}
+
+// call runs the handler, turning a panic into an error so the worker survives
+func (q *Queue) call(ctx context.Context, job *model.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	handler, ok := q.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("no handler for job kind %q", job.Kind)
+	}
+	return handler(ctx, job)
+}
+
+// runScheduler queues the recurring jobs that are due and prunes old jobs until ctx is cancelled
+func (q *Queue) runScheduler(ctx context.Context) {
+	names := make([]string, 0, len(q.schedules))
+	for name := range q.schedules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now().UTC()
+		fired, err := q.repo.FireSchedules(ctx, names, now, func(schedule *model.JobSchedule) (*model.Job, time.Time) {
+			entry := q.schedules[schedule.Name]
+			job := &model.Job{
+				Kind:        entry.kind,
+				Payload:     entry.payload,
+				Status:      model.JobPending,
+				MaxAttempts: q.cfg.MaxAttempts,
+				RunAt:       now,
+				Schedule:    schedule.Name,
+			}
+			return job, entry.schedule.Next(now)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "job schedules failed", "error", err)
+		}
+		if fired > 0 {
+			q.Wake()
+		}
+
+		if q.cfg.Retention > 0 && now.Sub(pruned) >= pruneInterval {
+			pruned = now
+			if _, err := q.repo.DeleteSucceeded(ctx, now.Add(-q.cfg.Retention)); err != nil {
+				slog.ErrorContext(ctx, "succeeded jobs were not cleaned up", "error", err)
+			}
+		}
+	}
+}
@@ -55,6 +55,19 @@ func JWTAuthMiddleware(jwtConfig config.JWTConfig) gin.HandlerFunc {
 	}
 }
 
+// OptionalJWTAuthMiddleware authenticates requests that carry a token like
+// JWTAuthMiddleware and lets anonymous requests through without claims
+func OptionalJWTAuthMiddleware(jwtConfig config.JWTConfig) gin.HandlerFunc {
+	auth := JWTAuthMiddleware(jwtConfig)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
 func GenerateJWT(userID uint, username string, role string, jwtConfig config.JWTConfig) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":      fmt.Sprintf("%d", userID),
@@ -115,3 +128,19 @@ func ExtractUserIDFromToken(c *gin.Context) (uint, error) {
 
 	return userID, nil
 }
+
+// RequireRole only lets through requests whose token carries the given role; it
+// must run after JWTAuthMiddleware
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, exists := c.Get("claims")
+		mapClaims, ok := claims.(jwt.MapClaims)
+		if !exists || !ok || mapClaims["role"] != role {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
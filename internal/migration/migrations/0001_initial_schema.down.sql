@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS blog_tags;
+DROP TABLE IF EXISTS contact_submissions;
+DROP TABLE IF EXISTS portfolio_projects;
+DROP TABLE IF EXISTS blog_posts;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS update_timestamp();
@@ -0,0 +1,113 @@
+-- Baseline schema. Every statement is idempotent so databases created by the
+-- old AutoMigrate or init-scripts setup can adopt versioned migrations.
+
+CREATE OR REPLACE FUNCTION update_timestamp()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.updated_at = CURRENT_TIMESTAMP;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    username TEXT NOT NULL,
+    email TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    role TEXT DEFAULT 'user',
+    active BOOLEAN DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS blog_posts (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    summary TEXT,
+    image_url TEXT,
+    published BOOLEAN DEFAULT FALSE,
+    publish_at TIMESTAMPTZ,
+    slug TEXT
+);
+
+CREATE TABLE IF NOT EXISTS blog_tags (
+    blog_post_id BIGINT NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (blog_post_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS portfolio_projects (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    project_type TEXT NOT NULL,
+    image_url TEXT,
+    project_url TEXT,
+    repo_url TEXT,
+    technologies JSONB,
+    featured BOOLEAN DEFAULT FALSE,
+    start_date TIMESTAMPTZ,
+    end_date TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS contact_submissions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    subject TEXT,
+    message TEXT NOT NULL,
+    read BOOLEAN DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_blog_posts_slug ON blog_posts(slug);
+CREATE INDEX IF NOT EXISTS idx_blog_posts_published ON blog_posts(published);
+CREATE INDEX IF NOT EXISTS idx_blog_posts_deleted_at ON blog_posts(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_portfolio_projects_type ON portfolio_projects(project_type);
+CREATE INDEX IF NOT EXISTS idx_portfolio_projects_featured ON portfolio_projects(featured);
+CREATE INDEX IF NOT EXISTS idx_portfolio_projects_deleted_at ON portfolio_projects(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_contact_submissions_read ON contact_submissions(read);
+CREATE INDEX IF NOT EXISTS idx_contact_submissions_deleted_at ON contact_submissions(deleted_at);
+
+CREATE OR REPLACE TRIGGER update_users_timestamp
+BEFORE UPDATE ON users
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE OR REPLACE TRIGGER update_tags_timestamp
+BEFORE UPDATE ON tags
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE OR REPLACE TRIGGER update_blog_posts_timestamp
+BEFORE UPDATE ON blog_posts
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE OR REPLACE TRIGGER update_portfolio_projects_timestamp
+BEFORE UPDATE ON portfolio_projects
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE OR REPLACE TRIGGER update_contact_submissions_timestamp
+BEFORE UPDATE ON contact_submissions
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
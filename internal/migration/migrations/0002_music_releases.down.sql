@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS streaming_links;
+DROP TABLE IF EXISTS music_credits;
+DROP TABLE IF EXISTS music_tracks;
+DROP TABLE IF EXISTS music_releases;
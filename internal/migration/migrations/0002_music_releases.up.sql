@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS music_releases (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    portfolio_project_id BIGINT NOT NULL REFERENCES portfolio_projects(id) ON DELETE CASCADE,
+    release_date TIMESTAMPTZ,
+    label TEXT
+);
+
+CREATE TABLE IF NOT EXISTS music_tracks (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    music_release_id BIGINT NOT NULL REFERENCES music_releases(id) ON DELETE CASCADE,
+    position BIGINT,
+    title TEXT NOT NULL,
+    duration_seconds BIGINT,
+    isrc TEXT,
+    audio_url TEXT
+);
+
+CREATE TABLE IF NOT EXISTS music_credits (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    music_release_id BIGINT NOT NULL REFERENCES music_releases(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    role TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS streaming_links (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    music_release_id BIGINT NOT NULL REFERENCES music_releases(id) ON DELETE CASCADE,
+    platform TEXT NOT NULL,
+    url TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_music_releases_portfolio_project_id ON music_releases(portfolio_project_id);
+CREATE INDEX IF NOT EXISTS idx_music_releases_deleted_at ON music_releases(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_music_tracks_music_release_id ON music_tracks(music_release_id);
+CREATE INDEX IF NOT EXISTS idx_music_tracks_deleted_at ON music_tracks(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_music_credits_music_release_id ON music_credits(music_release_id);
+CREATE INDEX IF NOT EXISTS idx_music_credits_deleted_at ON music_credits(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_streaming_links_music_release_id ON streaming_links(music_release_id);
+CREATE INDEX IF NOT EXISTS idx_streaming_links_deleted_at ON streaming_links(deleted_at);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS project_types (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    description TEXT,
+    sort_order BIGINT DEFAULT 0,
+    icon TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_types_slug ON project_types(slug);
+CREATE INDEX IF NOT EXISTS idx_project_types_deleted_at ON project_types(deleted_at);
+
+CREATE OR REPLACE TRIGGER update_project_types_timestamp
+BEFORE UPDATE ON project_types
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+INSERT INTO project_types (name, slug, sort_order, icon) VALUES
+    ('Coding', 'coding', 1, 'code'),
+    ('Music', 'music', 2, 'music')
+ON CONFLICT (slug) DO NOTHING;
+
+-- Earlier versions accepted any spelling of the type
+UPDATE portfolio_projects SET project_type = LOWER(TRIM(project_type))
+WHERE project_type <> LOWER(TRIM(project_type));
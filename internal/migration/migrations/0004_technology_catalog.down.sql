@@ -0,0 +1,12 @@
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS technologies JSONB;
+
+UPDATE portfolio_projects SET technologies = (
+    SELECT COALESCE(jsonb_agg(technologies.name ORDER BY technologies.name), '[]'::jsonb)
+    FROM project_technologies
+    JOIN technologies ON technologies.id = project_technologies.technology_id
+    WHERE project_technologies.portfolio_project_id = portfolio_projects.id
+);
+
+DROP TABLE IF EXISTS project_technologies;
+DROP TABLE IF EXISTS technology_aliases;
+DROP TABLE IF EXISTS technologies;
@@ -0,0 +1,74 @@
+CREATE TABLE IF NOT EXISTS technologies (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS technology_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    technology_id BIGINT NOT NULL REFERENCES technologies(id) ON DELETE CASCADE,
+    alias TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS project_technologies (
+    portfolio_project_id BIGINT NOT NULL REFERENCES portfolio_projects(id) ON DELETE CASCADE,
+    technology_id BIGINT NOT NULL REFERENCES technologies(id) ON DELETE CASCADE,
+    PRIMARY KEY (portfolio_project_id, technology_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_name ON technologies(name);
+CREATE INDEX IF NOT EXISTS idx_technologies_deleted_at ON technologies(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technology_aliases_alias ON technology_aliases(alias);
+CREATE INDEX IF NOT EXISTS idx_technology_aliases_technology_id ON technology_aliases(technology_id);
+CREATE INDEX IF NOT EXISTS idx_technology_aliases_deleted_at ON technology_aliases(deleted_at);
+
+CREATE OR REPLACE TRIGGER update_technologies_timestamp
+BEFORE UPDATE ON technologies
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+INSERT INTO technologies (name) VALUES
+    ('Go'), ('JavaScript'), ('TypeScript'), ('React'),
+    ('Node.js'), ('PostgreSQL'), ('Python'), ('Kubernetes')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO technology_aliases (technology_id, alias)
+SELECT technologies.id, aliases.alias
+FROM (VALUES
+    ('Go', 'golang'), ('JavaScript', 'js'), ('JavaScript', 'ecmascript'),
+    ('TypeScript', 'ts'), ('React', 'reactjs'), ('React', 'react.js'),
+    ('Node.js', 'node'), ('Node.js', 'nodejs'), ('PostgreSQL', 'postgres'),
+    ('PostgreSQL', 'psql'), ('Python', 'py'), ('Kubernetes', 'k8s')
+) AS aliases(name, alias)
+JOIN technologies ON technologies.name = aliases.name
+ON CONFLICT (alias) DO NOTHING;
+
+-- Move the hand-serialized JSON list into the catalog, then drop it
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'portfolio_projects' AND column_name = 'technologies'
+    ) THEN
+        INSERT INTO technologies (name)
+        SELECT DISTINCT ON (LOWER(TRIM(legacy.name))) TRIM(legacy.name)
+        FROM portfolio_projects, jsonb_array_elements_text(COALESCE(portfolio_projects.technologies::jsonb, '[]'::jsonb)) AS legacy(name)
+        WHERE TRIM(legacy.name) <> ''
+        AND NOT EXISTS (SELECT 1 FROM technologies WHERE LOWER(technologies.name) = LOWER(TRIM(legacy.name)))
+        AND NOT EXISTS (SELECT 1 FROM technology_aliases WHERE technology_aliases.alias = LOWER(TRIM(legacy.name)));
+
+        INSERT INTO project_technologies (portfolio_project_id, technology_id)
+        SELECT DISTINCT portfolio_projects.id, technologies.id
+        FROM portfolio_projects, jsonb_array_elements_text(COALESCE(portfolio_projects.technologies::jsonb, '[]'::jsonb)) AS legacy(name)
+        JOIN technologies ON LOWER(technologies.name) = LOWER(TRIM(legacy.name))
+            OR technologies.id IN (SELECT technology_id FROM technology_aliases WHERE alias = LOWER(TRIM(legacy.name)))
+        ON CONFLICT DO NOTHING;
+
+        ALTER TABLE portfolio_projects DROP COLUMN technologies;
+    END IF;
+END
+$$;
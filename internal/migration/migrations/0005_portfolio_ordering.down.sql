@@ -0,0 +1 @@
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS sort_order;
@@ -0,0 +1,3 @@
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS sort_order BIGINT DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_projects_sort_order ON portfolio_projects(sort_order);
@@ -0,0 +1,3 @@
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS repo_synced_at;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS last_commit_at;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS stars;
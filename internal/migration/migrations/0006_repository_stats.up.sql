@@ -0,0 +1,3 @@
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS stars BIGINT DEFAULT 0;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS last_commit_at TIMESTAMPTZ;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS repo_synced_at TIMESTAMPTZ;
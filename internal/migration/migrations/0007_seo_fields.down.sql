@@ -0,0 +1,11 @@
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS og_image_url;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS noindex;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS canonical_url;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE portfolio_projects DROP COLUMN IF EXISTS meta_title;
+
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS og_image_url;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS noindex;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS canonical_url;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,11 @@
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS meta_title TEXT;
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS meta_description TEXT;
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS canonical_url TEXT;
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS noindex BOOLEAN DEFAULT false;
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS og_image_url TEXT;
+
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS meta_title TEXT;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS meta_description TEXT;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS canonical_url TEXT;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS noindex BOOLEAN DEFAULT false;
+ALTER TABLE portfolio_projects ADD COLUMN IF NOT EXISTS og_image_url TEXT;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_blog_posts_series_id;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS series_position;
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS series (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    title TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    description TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_slug ON series(slug);
+CREATE INDEX IF NOT EXISTS idx_series_deleted_at ON series(deleted_at);
+
+CREATE OR REPLACE TRIGGER update_series_timestamp
+BEFORE UPDATE ON series
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS series_id BIGINT REFERENCES series(id) ON DELETE SET NULL;
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS series_position BIGINT DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_blog_posts_series_id ON blog_posts(series_id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS related_pins (
+    post_id BIGINT NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    related_post_id BIGINT NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    position BIGINT DEFAULT 0,
+    PRIMARY KEY (post_id, related_post_id)
+);
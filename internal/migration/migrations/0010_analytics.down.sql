@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS analytics_referrers_daily;
+DROP TABLE IF EXISTS analytics_daily;
+DROP TABLE IF EXISTS analytics_salts;
+DROP TABLE IF EXISTS page_views;
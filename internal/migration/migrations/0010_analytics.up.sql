@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS page_views (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    content_type TEXT NOT NULL,
+    content_key TEXT NOT NULL,
+    event TEXT NOT NULL,
+    visitor_hash TEXT NOT NULL,
+    referrer_host TEXT NOT NULL DEFAULT '',
+    utm_source TEXT NOT NULL DEFAULT '',
+    utm_medium TEXT NOT NULL DEFAULT '',
+    utm_campaign TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_page_views_created_at ON page_views(created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_salts (
+    day DATE PRIMARY KEY,
+    salt TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS analytics_daily (
+    day DATE NOT NULL,
+    content_type TEXT NOT NULL,
+    content_key TEXT NOT NULL,
+    event TEXT NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    visitors BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, content_type, content_key, event)
+);
+
+CREATE TABLE IF NOT EXISTS analytics_referrers_daily (
+    day DATE NOT NULL,
+    content_type TEXT NOT NULL,
+    content_key TEXT NOT NULL,
+    referrer_host TEXT NOT NULL DEFAULT '',
+    utm_source TEXT NOT NULL DEFAULT '',
+    utm_medium TEXT NOT NULL DEFAULT '',
+    utm_campaign TEXT NOT NULL DEFAULT '',
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, content_type, content_key, referrer_host, utm_source, utm_medium, utm_campaign)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE blog_posts DROP COLUMN IF EXISTS comments_closed;
+
+DROP TABLE IF EXISTS comments;
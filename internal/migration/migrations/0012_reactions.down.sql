@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reaction_votes;
+DROP TABLE IF EXISTS reaction_counts;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reaction_counts (
+    content_type TEXT NOT NULL,
+    content_id BIGINT NOT NULL,
+    emoji TEXT NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (content_type, content_id, emoji)
+);
+
+CREATE TABLE IF NOT EXISTS reaction_votes (
+    content_type TEXT NOT NULL,
+    content_id BIGINT NOT NULL,
+    emoji TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (content_type, content_id, emoji, fingerprint)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS newsletter_issue_posts;
+DROP TABLE IF EXISTS newsletter_issues;
+DROP TABLE IF EXISTS subscribers;
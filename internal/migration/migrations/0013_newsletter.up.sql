@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS subscribers (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    email TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    confirm_token TEXT,
+    unsubscribe_token TEXT NOT NULL,
+    confirmation_sent_at TIMESTAMPTZ,
+    confirmed_at TIMESTAMPTZ,
+    unsubscribed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscribers_email ON subscribers(email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscribers_unsubscribe_token ON subscribers(unsubscribe_token);
+CREATE INDEX IF NOT EXISTS idx_subscribers_confirm_token ON subscribers(confirm_token);
+CREATE INDEX IF NOT EXISTS idx_subscribers_status ON subscribers(status);
+CREATE INDEX IF NOT EXISTS idx_subscribers_deleted_at ON subscribers(deleted_at);
+
+CREATE OR REPLACE TRIGGER update_subscribers_timestamp
+BEFORE UPDATE ON subscribers
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE TABLE IF NOT EXISTS newsletter_issues (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    subject TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'sending',
+    last_subscriber_id BIGINT NOT NULL DEFAULT 0,
+    sent_count BIGINT NOT NULL DEFAULT 0,
+    failed_count BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_newsletter_issues_status ON newsletter_issues(status);
+
+CREATE OR REPLACE TRIGGER update_newsletter_issues_timestamp
+BEFORE UPDATE ON newsletter_issues
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- A post is only ever part of one issue
+CREATE TABLE IF NOT EXISTS newsletter_issue_posts (
+    newsletter_issue_id BIGINT NOT NULL REFERENCES newsletter_issues(id) ON DELETE CASCADE,
+    blog_post_id BIGINT NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    PRIMARY KEY (newsletter_issue_id, blog_post_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_newsletter_issue_posts_blog_post_id ON newsletter_issue_posts(blog_post_id);
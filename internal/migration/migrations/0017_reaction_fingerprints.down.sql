@@ -0,0 +1,2 @@
+-- The removed fingerprints cannot be restored
+SELECT 1;
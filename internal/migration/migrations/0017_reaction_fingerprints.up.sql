@@ -0,0 +1,3 @@
+-- Fingerprints were hashed without a secret, which is cheap to reverse.
+-- reaction_counts keeps the totals; earlier voters may react once more.
+DELETE FROM reaction_votes;
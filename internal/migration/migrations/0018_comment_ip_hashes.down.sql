@@ -0,0 +1,2 @@
+-- The removed hashes cannot be restored
+SELECT 1;
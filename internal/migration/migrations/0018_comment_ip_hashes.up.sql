@@ -0,0 +1,3 @@
+-- Addresses were hashed without a secret, which is cheap to reverse. They only
+-- feed the rate limit, so clearing them loses nothing that lasts.
+UPDATE comments SET ip_hash = NULL WHERE ip_hash IS NOT NULL;
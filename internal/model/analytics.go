@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// Content types and events that analytics accepts
+const (
+	AnalyticsContentPost    = "post"
+	AnalyticsContentProject = "project"
+
+	AnalyticsEventView = "view"
+	AnalyticsEventRead = "read"
+)
+
+// PageView is a raw analytics event. It only lives until it has been rolled
+// up into the daily tables, and identifies visitors by a hash salted per day.
+type PageView struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	ContentKey   string    `json:"content_key" gorm:"not null"` // Post slug or project ID
+	Event        string    `json:"event" gorm:"not null"`
+	VisitorHash  string    `json:"-" gorm:"not null"`
+	ReferrerHost string    `json:"referrer_host" gorm:"not null;default:''"`
+	UTMSource    string    `json:"utm_source" gorm:"column:utm_source;not null;default:''"`
+	UTMMedium    string    `json:"utm_medium" gorm:"column:utm_medium;not null;default:''"`
+	UTMCampaign  string    `json:"utm_campaign" gorm:"column:utm_campaign;not null;default:''"`
+}
+
+// AnalyticsSalt is the random salt of one UTC day, deleted once the day has been rolled up
+type AnalyticsSalt struct {
+	Day  time.Time `gorm:"primaryKey;type:date"`
+	Salt string    `gorm:"not null"`
+}
+
+// PageViewRequest is sent by the frontend when content is viewed or read to the end
+type PageViewRequest struct {
+	Type        string `json:"type" binding:"required"`
+	Key         string `json:"key" binding:"required"`
+	Event       string `json:"event"` // Defaults to view
+	Referrer    string `json:"referrer"`
+	UTMSource   string `json:"utm_source"`
+	UTMMedium   string `json:"utm_medium"`
+	UTMCampaign string `json:"utm_campaign"`
+}
+
+// AnalyticsQuery selects a range of the daily rollups
+type AnalyticsQuery struct {
+	ContentType string
+	ContentKey  string
+	Event       string
+	From        time.Time // Inclusive UTC day
+	To          time.Time // Inclusive UTC day
+	Limit       int
+	ByUTM       bool // Group referrer stats by UTM parameters instead of referrer host
+}
+
+// ContentStat is the traffic of one piece of content over a range
+type ContentStat struct {
+	ContentType string `json:"content_type"`
+	ContentKey  string `json:"content_key"`
+	Views       int64  `json:"views"`
+	Visitors    int64  `json:"visitors"` // Sum of daily unique visitors
+}
+
+// AnalyticsPoint is the traffic of one day
+type AnalyticsPoint struct {
+	Day      string `json:"day"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// ReferrerStat is the traffic from one referrer host or UTM combination
+type ReferrerStat struct {
+	ReferrerHost string `json:"referrer_host,omitempty"`
+	UTMSource    string `json:"utm_source,omitempty"`
+	UTMMedium    string `json:"utm_medium,omitempty"`
+	UTMCampaign  string `json:"utm_campaign,omitempty"`
+	Views        int64  `json:"views"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+)
+
+// Archive formats for the per-entity data files
+const (
+	ArchiveFormatJSON   = "json"
+	ArchiveFormatNDJSON = "ndjson"
+)
+
+// ArchiveManifest describes the contents of an export archive
+type ArchiveManifest struct {
+	Version                int            `json:"version"`
+	ExportedAt             time.Time      `json:"exported_at"`
+	Format                 string         `json:"format"`
+	IncludesPasswordHashes bool           `json:"includes_password_hashes"`
+	IncludesMedia          bool           `json:"includes_media"`
+	Counts                 map[string]int `json:"counts"`
+}
+
+// ExportOptions controls what goes into an export archive
+type ExportOptions struct {
+	Format                string
+	IncludePasswordHashes bool
+	IncludeMedia          bool
+}
+
+// ArchiveUser is the exported form of a User, optionally carrying the password hash
+type ArchiveUser struct {
+	User
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// ArchivePost is the exported form of a BlogPost, referring to its series by slug
+type ArchivePost struct {
+	BlogPost
+	SeriesSlug string `json:"series_slug,omitempty"`
+}
+
+// ImportReport summarizes what an import did, or would do when DryRun is set
+type ImportReport struct {
+	DryRun   bool                           `json:"dry_run"`
+	Entities map[string]*ImportEntityReport `json:"entities"`
+	// SkippedMedia counts the files under media/, which are not restored
+	SkippedMedia int           `json:"skipped_media"`
+	Errors       []ImportError `json:"errors"`
+}
+
+// ImportEntityReport counts the outcome per record for one entity type
+type ImportEntityReport struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+}
+
+// ImportError reports a record that could not be imported
+type ImportError struct {
+	Entity string `json:"entity"`
+	Key    string `json:"key"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Moderation states of a comment; only approved comments are public
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// Comment is a reader's comment on a blog post, optionally replying to another comment
+type Comment struct {
+	gorm.Model
+	PostID      uint   `json:"post_id" gorm:"index;not null"`
+	ParentID    *uint  `json:"parent_id" gorm:"index"`
+	UserID      *uint  `json:"user_id"` // Set when the author was logged in
+	AuthorName  string `json:"author_name" gorm:"not null"`
+	AuthorEmail string `json:"author_email" gorm:"not null"` // Only shown to admins
+	AuthorURL   string `json:"author_url"`
+	Content     string `json:"content" gorm:"type:text;not null"`
+	Status      string `json:"status" gorm:"not null;default:pending;index"`
+	SpamReason  string `json:"spam_reason,omitempty"` // Why the built-in checks flagged the comment
+	IPHash      string `json:"-" gorm:"column:ip_hash;index"`
+	UserAgent   string `json:"user_agent"`
+}
+
+// CommentRequest is a comment submitted by a reader. Name and email are taken
+// from the account when the request is authenticated.
+type CommentRequest struct {
+	ParentID    *uint  `json:"parent_id"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	AuthorURL   string `json:"author_url"`
+	Content     string `json:"content" binding:"required"`
+	Website     string `json:"website"` // Honeypot, hidden from humans by the frontend
+}
+
+// PublicComment is an approved comment as shown to readers, with its approved replies
+type PublicComment struct {
+	ID         uint            `json:"id"`
+	ParentID   *uint           `json:"parent_id"`
+	AuthorName string          `json:"author_name"`
+	AuthorURL  string          `json:"author_url"`
+	Registered bool            `json:"registered"` // Written by a logged in user
+	Content    string          `json:"content"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Replies    []PublicComment `json:"replies"`
+}
+
+// CommentThread is the public comment tree of a post
+type CommentThread struct {
+	PostID   uint            `json:"post_id"`
+	Closed   bool            `json:"closed"`
+	Count    int             `json:"count"`
+	Comments []PublicComment `json:"comments"`
+}
+
+// CommentFilter narrows the admin comment list; zero values match everything
+type CommentFilter struct {
+	Status string
+	PostID uint
+}
+
+// ModerateCommentsRequest moves comments into a moderation state in bulk
+type ModerateCommentsRequest struct {
+	IDs    []uint `json:"ids" binding:"required"`
+	Status string `json:"status" binding:"required"`
+}
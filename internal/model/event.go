@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// DomainEvent is something that happened to the site's content. Events are
+// stored in the outbox by the same transaction as the write that caused them.
+type DomainEvent interface {
+	EventName() string
+}
+
+// Outbox states; failed events have used up their attempts
+const (
+	OutboxPending   = "pending"
+	OutboxProcessed = "processed"
+	OutboxFailed    = "failed"
+)
+
+// OutboxEvent is a stored domain event waiting to be passed to the subscribers
+type OutboxEvent struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Event         string     `json:"event" gorm:"not null"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"`
+	Status        string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+	Handled       []string   `json:"handled" gorm:"serializer:json"` // Subscribers that are done with the event
+	Error         string     `json:"error"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Job states; dead jobs have used up their attempts and wait for an admin
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead"
+)
+
+// Job kinds
+const (
+	JobKindAnalyticsRollup        = "analytics.rollup"
+	JobKindBlogPublish            = "blog.publish"
+	JobKindNewsletterConfirmation = "newsletter.confirmation"
+	JobKindNewsletterDigest       = "newsletter.digest"
+	JobKindRepositoryRefresh      = "repository.refresh"
+)
+
+// Job is a unit of background work, picked up by the first free worker
+type Job struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Kind        string     `json:"kind" gorm:"not null;index"`
+	Payload     string     `json:"payload" gorm:"type:text;not null"`
+	Status      string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	RunAt       time.Time  `json:"run_at" gorm:"index"`
+	LockedUntil *time.Time `json:"locked_until"` // A running job past this is taken over by another worker
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	LastError   string     `json:"last_error"`
+	Schedule    string     `json:"schedule,omitempty"` // The recurring schedule that queued the job
+}
+
+// BlogPublishPayload announces a scheduled post once its PublishAt has come
+type BlogPublishPayload struct {
+	PostID    uint      `json:"post_id"`
+	PublishAt time.Time `json:"publish_at"` // The job is stale when the post was rescheduled since
+}
+
+// JobSchedule records when a recurring job runs next, so only one instance queues it
+type JobSchedule struct {
+	Name      string     `json:"name" gorm:"primaryKey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Spec      string     `json:"spec" gorm:"not null"`
+	Kind      string     `json:"kind" gorm:"not null"`
+	NextRunAt time.Time  `json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at"`
+}
+
+// JobFilter narrows the admin job list
+type JobFilter struct {
+	Status string
+	Kind   string
+}
@@ -0,0 +1,33 @@
+package model
+
+// Outcomes of importing a single Markdown file
+const (
+	MarkdownStatusCreated  = "created"
+	MarkdownStatusUpdated  = "updated"
+	MarkdownStatusConflict = "conflict"
+	MarkdownStatusInvalid  = "invalid"
+	MarkdownStatusFailed   = "failed"
+)
+
+// MarkdownFile is one Markdown document with optional YAML front matter
+type MarkdownFile struct {
+	Name    string
+	Content []byte
+}
+
+// MarkdownImportReport summarizes a Markdown import, or what it would do when DryRun is set
+type MarkdownImportReport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Failed  int                  `json:"failed"`
+	Files   []MarkdownFileResult `json:"files"`
+}
+
+// MarkdownFileResult reports the outcome for one file
+type MarkdownFileResult struct {
+	File   string `json:"file"`
+	Slug   string `json:"slug,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
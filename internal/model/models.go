@@ -16,6 +16,90 @@ type BlogPost struct {
 	PublishAt time.Time `json:"publish_at"`
 	Slug      string    `json:"slug" gorm:"uniqueIndex"`
 	Tags      []Tag     `json:"tags" gorm:"many2many:blog_tags;"`
+	SEO       SEO       `json:"seo" gorm:"embedded"`
+	// Membership is managed through PUT /api/series/:id/posts
+	SeriesID       *uint            `json:"series_id" gorm:"index"`
+	SeriesPosition int              `json:"series_position" gorm:"default:0"`
+	SeriesNav      *SeriesNav       `json:"series_nav,omitempty" gorm:"-"` // Only filled when fetched by slug
+	Related        []RelatedPost    `json:"related,omitempty" gorm:"-"`    // Only filled when fetched by slug
+	CommentsClosed bool             `json:"comments_closed" gorm:"default:false"`
+	CommentCount   int64            `json:"comment_count" gorm:"->;-:migration"` // Approved comments, filled by list and slug queries
+	Reactions      map[string]int64 `json:"reactions" gorm:"-"`                  // Count per emoji, filled by list and slug queries
+	ReactionTotal  int64            `json:"reaction_total" gorm:"-"`
+}
+
+// Sort options for the blog post listing
+const (
+	BlogSortCreatedAt = "created_at"
+	BlogSortReactions = "reactions" // Most reactions first
+)
+
+// RelatedPost is a recommendation shown below a post
+type RelatedPost struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Summary   string    `json:"summary"`
+	ImageURL  string    `json:"image_url"`
+	PublishAt time.Time `json:"publish_at"`
+	Pinned    bool      `json:"pinned"`
+}
+
+// RelatedPin is an editor's choice of related post, listed before the computed ones
+type RelatedPin struct {
+	PostID        uint `gorm:"primaryKey"`
+	RelatedPostID uint `gorm:"primaryKey"`
+	Position      int  `gorm:"default:0"`
+}
+
+// RelatedPinsRequest sets the pinned related posts of a post in display order
+type RelatedPinsRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"required"`
+}
+
+// SEO holds optional overrides for search engines and link previews
+type SEO struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description" gorm:"type:text"`
+	CanonicalURL    string `json:"canonical_url"`
+	NoIndex         bool   `json:"noindex" gorm:"column:noindex;default:false"`
+	OGImageURL      string `json:"og_image_url" gorm:"column:og_image_url"` // A social card is generated when empty
+}
+
+// Series groups blog posts into an ordered, multi-part collection
+type Series struct {
+	gorm.Model
+	Title       string       `json:"title" gorm:"not null"`
+	Slug        string       `json:"slug" gorm:"uniqueIndex;not null"`
+	Description string       `json:"description" gorm:"type:text"`
+	PostCount   int64        `json:"post_count" gorm:"->;-:migration"` // Visible posts, only filled by count queries
+	Parts       []SeriesPart `json:"parts,omitempty" gorm:"-"`
+}
+
+// SeriesPart is a visible post of a series, numbered from 1 in series order
+type SeriesPart struct {
+	Part      int       `json:"part"`
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Summary   string    `json:"summary"`
+	PublishAt time.Time `json:"publish_at"`
+}
+
+// SeriesNav places a post within its series
+type SeriesNav struct {
+	ID       uint        `json:"id"`
+	Title    string      `json:"title"`
+	Slug     string      `json:"slug"`
+	Part     int         `json:"part"` // 0 when the post itself is not visible yet
+	Total    int         `json:"total"`
+	Previous *SeriesPart `json:"previous"`
+	Next     *SeriesPart `json:"next"`
+}
+
+// SeriesPostsRequest sets the posts of a series in reading order
+type SeriesPostsRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"required"`
 }
 
 type Tag struct {
@@ -26,17 +110,122 @@ type Tag struct {
 
 type PortfolioProject struct {
 	gorm.Model
-	Title        string    `json:"title" gorm:"not null"`
-	Description  string    `json:"description" gorm:"type:text;not null"`
-	ProjectType  string    `json:"project_type" gorm:"not null"` // "coding" or "music"
-	ImageURL     string    `json:"image_url"`
-	ProjectURL   string    `json:"project_url"`
-	RepoURL      string    `json:"repo_url"`
-	Technologies []string  `json:"technologies" gorm:"-"` // Will be handled with JSON serialization
-	TechJSON     string    `json:"-" gorm:"column:technologies;type:json"`
-	Featured     bool      `json:"featured" gorm:"default:false"`
-	StartDate    time.Time `json:"start_date"`
-	EndDate      time.Time `json:"end_date"`
+	Title             string           `json:"title" gorm:"not null"`
+	Description       string           `json:"description" gorm:"type:text;not null"`
+	ProjectType       string           `json:"project_type" gorm:"not null"` // Slug of a ProjectType
+	ImageURL          string           `json:"image_url"`
+	ProjectURL        string           `json:"project_url"`
+	RepoURL           string           `json:"repo_url"`
+	Stars             int              `json:"stars" gorm:"default:0"` // Synced from the git host of RepoURL
+	LastCommitAt      *time.Time       `json:"last_commit_at"`
+	RepoSyncedAt      *time.Time       `json:"repo_synced_at"`
+	Technologies      []string         `json:"technologies" gorm:"-"` // Canonical names, resolved through the technology catalog
+	TechnologyRecords []Technology     `json:"-" gorm:"many2many:project_technologies;"`
+	Featured          bool             `json:"featured" gorm:"default:false"`
+	SortOrder         int              `json:"sort_order" gorm:"default:0;index"` // Position in the manual ordering
+	StartDate         time.Time        `json:"start_date"`
+	EndDate           time.Time        `json:"end_date"`
+	Music             *MusicRelease    `json:"music,omitempty"`
+	SEO               SEO              `json:"seo" gorm:"embedded"`
+	Reactions         map[string]int64 `json:"reactions" gorm:"-"` // Count per emoji, filled by list and detail queries
+	ReactionTotal     int64            `json:"reaction_total" gorm:"-"`
+}
+
+// Slugs of the project types that ship with every installation
+const (
+	ProjectTypeCoding = "coding"
+	ProjectTypeMusic  = "music"
+)
+
+// ProjectType is an admin-managed category that PortfolioProject.ProjectType refers to by slug
+type ProjectType struct {
+	gorm.Model
+	Name         string `json:"name" gorm:"not null"`
+	Slug         string `json:"slug" gorm:"uniqueIndex;not null"`
+	Description  string `json:"description" gorm:"type:text"`
+	SortOrder    int    `json:"sort_order" gorm:"default:0"`
+	Icon         string `json:"icon"`
+	ProjectCount int64  `json:"project_count" gorm:"->;-:migration"` // Only filled by count queries
+}
+
+// Technology is a catalog entry that portfolio projects are tagged with
+type Technology struct {
+	gorm.Model
+	Name         string            `json:"name" gorm:"uniqueIndex;not null"`
+	Aliases      []string          `json:"aliases" gorm:"-"`
+	AliasRecords []TechnologyAlias `json:"-"`
+	ProjectCount int64             `json:"project_count" gorm:"->;-:migration"` // Only filled by count queries
+}
+
+// TechnologyAlias maps an alternative spelling such as "golang" onto a Technology
+type TechnologyAlias struct {
+	gorm.Model
+	TechnologyID uint   `json:"-" gorm:"index;not null"`
+	Alias        string `json:"alias" gorm:"uniqueIndex;not null"` // Stored lower-cased
+}
+
+// ProjectImportRequest represents a request to draft a project from a git repository
+type ProjectImportRequest struct {
+	RepoURL string `json:"repo_url" binding:"required"`
+}
+
+// Sort options for the portfolio project listing
+const (
+	ProjectSortCreatedAt = "created_at"
+	ProjectSortStartDate = "start_date"
+	ProjectSortEndDate   = "end_date"
+	ProjectSortTitle     = "title"
+	ProjectSortManual    = "manual" // Featured projects first, then by SortOrder
+	ProjectSortReactions = "reactions"
+)
+
+// ProjectFilter narrows down and orders the portfolio project listing
+type ProjectFilter struct {
+	ProjectType  string
+	Technologies []string // Projects must use every listed technology
+	Featured     *bool
+	Sort         string
+	Descending   *bool // Overrides the natural direction of Sort
+}
+
+// ProjectOrderRequest represents a bulk reorder of portfolio projects
+type ProjectOrderRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// MusicRelease holds the metadata that only applies to music projects
+type MusicRelease struct {
+	gorm.Model
+	PortfolioProjectID uint            `json:"-" gorm:"uniqueIndex;not null"`
+	ReleaseDate        time.Time       `json:"release_date"`
+	Label              string          `json:"label"`
+	Tracks             []MusicTrack    `json:"tracks"`
+	Credits            []MusicCredit   `json:"credits"`
+	StreamingLinks     []StreamingLink `json:"streaming_links"`
+}
+
+type MusicTrack struct {
+	gorm.Model
+	MusicReleaseID  uint   `json:"-" gorm:"index;not null"`
+	Position        int    `json:"position"`
+	Title           string `json:"title" gorm:"not null"`
+	DurationSeconds int    `json:"duration_seconds"`
+	ISRC            string `json:"isrc" gorm:"column:isrc"`
+	AudioURL        string `json:"audio_url"`
+}
+
+type MusicCredit struct {
+	gorm.Model
+	MusicReleaseID uint   `json:"-" gorm:"index;not null"`
+	Name           string `json:"name" gorm:"not null"`
+	Role           string `json:"role" gorm:"not null"` // e.g. "producer", "mixing", "vocals"
+}
+
+type StreamingLink struct {
+	gorm.Model
+	MusicReleaseID uint   `json:"-" gorm:"index;not null"`
+	Platform       string `json:"platform" gorm:"not null"` // e.g. "spotify", "bandcamp"
+	URL            string `json:"url" gorm:"not null"`
 }
 
 type ContactSubmission struct {
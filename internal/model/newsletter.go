@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Subscriber states; only active subscribers receive digests
+const (
+	SubscriberPending      = "pending" // Waiting for the confirmation link to be opened
+	SubscriberActive       = "active"
+	SubscriberUnsubscribed = "unsubscribed"
+)
+
+// Newsletter issue states
+const (
+	NewsletterIssueSending = "sending"
+	NewsletterIssueSent    = "sent"
+)
+
+// Subscriber is a newsletter recipient
+type Subscriber struct {
+	gorm.Model
+	Email              string     `json:"email" gorm:"uniqueIndex;not null"`
+	Status             string     `json:"status" gorm:"not null;default:pending;index"`
+	ConfirmToken       string     `json:"-" gorm:"index"` // Cleared once confirmed
+	UnsubscribeToken   string     `json:"-" gorm:"uniqueIndex;not null"`
+	ConfirmationSentAt *time.Time `json:"confirmation_sent_at"`
+	ConfirmedAt        *time.Time `json:"confirmed_at"`
+	UnsubscribedAt     *time.Time `json:"unsubscribed_at"`
+}
+
+// SubscribeRequest asks for a confirmation email
+type SubscribeRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// NewsletterIssue is one digest of newly published posts. Sending walks the
+// active subscribers by ID, so an interrupted issue resumes after LastSubscriberID.
+type NewsletterIssue struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	Subject          string     `json:"subject" gorm:"not null"`
+	Status           string     `json:"status" gorm:"not null;default:sending;index"`
+	LastSubscriberID uint       `json:"-" gorm:"not null;default:0"`
+	SentCount        int        `json:"sent_count" gorm:"not null;default:0"`
+	FailedCount      int        `json:"failed_count" gorm:"not null;default:0"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	Posts            []BlogPost `json:"posts" gorm:"many2many:newsletter_issue_posts;"`
+}
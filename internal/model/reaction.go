@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// Content types that can receive reactions
+const (
+	ReactionContentPost    = "post"
+	ReactionContentProject = "project"
+)
+
+// ReactionCount is the aggregate counter of one emoji on one piece of content
+type ReactionCount struct {
+	ContentType string `gorm:"primaryKey"`
+	ContentID   uint   `gorm:"primaryKey"`
+	Emoji       string `gorm:"primaryKey"`
+	Count       int64  `gorm:"not null;default:0"`
+}
+
+// ReactionVote remembers that a visitor reacted, so each visitor counts once per emoji
+type ReactionVote struct {
+	ContentType string `gorm:"primaryKey"`
+	ContentID   uint   `gorm:"primaryKey"`
+	Emoji       string `gorm:"primaryKey"`
+	Fingerprint string `gorm:"primaryKey"` // Hash of IP and user agent
+	CreatedAt   time.Time
+}
+
+// ReactionRequest is an anonymous reaction sent by a visitor
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// ReactionSummary lists the reactions of one piece of content
+type ReactionSummary struct {
+	Reactions map[string]int64 `json:"reactions"`
+	Total     int64            `json:"total"`
+}
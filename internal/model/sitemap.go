@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Content types that get their own sitemap
+const (
+	SitemapPosts    = "posts"
+	SitemapTags     = "tags"
+	SitemapProjects = "projects"
+)
+
+// SitemapEntry is one piece of content to list, identified by its slug, tag name or ID
+type SitemapEntry struct {
+	Key     string
+	LastMod time.Time
+}
+
+// SitemapIndex is the <sitemapindex> document served at /sitemap.xml
+type SitemapIndex struct {
+	XMLName  xml.Name         `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []SitemapIndexed `xml:"sitemap"`
+}
+
+// SitemapIndexed points at one sitemap from the index
+type SitemapIndexed struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SitemapURLSet is a <urlset> document listing pages of one content type
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is a single page in a SitemapURLSet
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// SocialCard is the Open Graph image of a post or project: either a generated
+// PNG or a redirect to the OGImageURL set on the content
+type SocialCard struct {
+	PNG         []byte
+	ETag        string
+	RedirectURL string
+}
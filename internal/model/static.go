@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// StaticExportReport summarizes a static site generation run
+type StaticExportReport struct {
+	Dir         string    `json:"dir"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Written     []string  `json:"written"`
+	Removed     []string  `json:"removed"`
+	Unchanged   int       `json:"unchanged"`
+}
+
+// JSONFeed is a feed in the JSON Feed 1.1 format
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem is a single post in a JSONFeed
+type JSONFeedItem struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url,omitempty"`
+	Title         string    `json:"title"`
+	ContentText   string    `json:"content_text"`
+	Summary       string    `json:"summary,omitempty"`
+	Image         string    `json:"image,omitempty"`
+	DatePublished time.Time `json:"date_published"`
+	DateModified  time.Time `json:"date_modified"`
+	Tags          []string  `json:"tags,omitempty"`
+}
@@ -14,6 +14,12 @@ type User struct {
 	Active       bool   `json:"active" gorm:"default:true"`
 }
 
+// Roles a user can have
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
 // AuthResponse represents the response for authentication endpoints
 type AuthResponse struct {
 	UserID       uint   `json:"user_id"`
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Events that webhooks can subscribe to, which are the names of the domain events in internal/event
+const (
+	WebhookEventAll             = "*"
+	WebhookEventBlogCreated     = "blog.created"
+	WebhookEventBlogPublished   = "blog.published"
+	WebhookEventBlogUpdated     = "blog.updated"
+	WebhookEventBlogDeleted     = "blog.deleted"
+	WebhookEventProjectCreated  = "project.created"
+	WebhookEventProjectUpdated  = "project.updated"
+	WebhookEventProjectDeleted  = "project.deleted"
+	WebhookEventContactReceived = "contact.received"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventBlogCreated,
+	WebhookEventBlogPublished,
+	WebhookEventBlogUpdated,
+	WebhookEventBlogDeleted,
+	WebhookEventProjectCreated,
+	WebhookEventProjectUpdated,
+	WebhookEventProjectDeleted,
+	WebhookEventContactReceived,
+}
+
+// Delivery states; failed deliveries have used up their attempts
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is an endpoint that receives signed POST requests for the events it subscribes to
+type Webhook struct {
+	gorm.Model
+	Name   string   `json:"name" gorm:"not null"`
+	URL    string   `json:"url" gorm:"not null"`
+	Secret string   `json:"secret" gorm:"not null"` // HMAC key, generated when left empty
+	Events []string `json:"events" gorm:"serializer:json;not null"`
+	Active bool     `json:"active" gorm:"default:true"`
+}
+
+// WebhookDelivery is one queued or attempted request to a webhook, and doubles as its log entry
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	WebhookID      uint       `json:"webhook_id" gorm:"index;not null"`
+	Event          string     `json:"event" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body" gorm:"type:text"` // Truncated
+	Error          string     `json:"error"`
+	DurationMS     int64      `json:"duration_ms" gorm:"column:duration_ms"`
+	RedeliveryOf   *uint      `json:"redelivery_of"` // The delivery this one repeats
+}
+
+// WebhookPayload is the JSON body sent to webhooks
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
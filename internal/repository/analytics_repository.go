@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// ContentExists keeps analytics to content that is actually visible
+func (r *analyticsRepository) ContentExists(ctx context.Context, contentType, key string, now time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	var err error
+	switch contentType {
+	case model.AnalyticsContentPost:
+		err = db.Model(&model.BlogPost{}).
+			Where("slug = ? AND published = ? AND publish_at <= ?", key, true, now).
+			Count(&count).Error
+	case model.AnalyticsContentProject:
+		err = db.Model(&model.PortfolioProject{}).Where("id = ?", key).Count(&count).Error
+	}
+	return count > 0, err
+}
+
+// Salt returns the salt of a UTC day, creating it on first use. Concurrent
+// callers agree on one salt through the primary key.
+func (r *analyticsRepository) Salt(ctx context.Context, day time.Time, candidate string) (string, error) {
+	db := r.db.WithContext(ctx)
+
+	salt := model.AnalyticsSalt{Day: day, Salt: candidate}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&salt).Error; err != nil {
+		return "", err
+	}
+
+	var stored model.AnalyticsSalt
+	if err := db.Where("day = ?", day).First(&stored).Error; err != nil {
+		return "", err
+	}
+	return stored.Salt, nil
+}
+
+func (r *analyticsRepository) DeleteSaltsBefore(ctx context.Context, day time.Time) error {
+	return r.db.WithContext(ctx).Where("day < ?", day).Delete(&model.AnalyticsSalt{}).Error
+}
+
+func (r *analyticsRepository) RecordView(ctx context.Context, view *model.PageView) error {
+	return r.db.WithContext(ctx).Create(view).Error
+}
+
+// Rollup recomputes the daily tables for every UTC day from since on out of the
+// raw events, so running it repeatedly is harmless
+func (r *analyticsRepository) Rollup(ctx context.Context, since time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO analytics_daily (day, content_type, content_key, event, views, visitors)
+			SELECT (created_at AT TIME ZONE 'UTC')::date, content_type, content_key, event,
+				COUNT(*), COUNT(DISTINCT visitor_hash)
+			FROM page_views
+			WHERE created_at >= ?
+			GROUP BY 1, 2, 3, 4
+			ON CONFLICT (day, content_type, content_key, event)
+			DO UPDATE SET views = EXCLUDED.views, visitors = EXCLUDED.visitors`, since).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO analytics_referrers_daily (day, content_type, content_key, referrer_host, utm_source, utm_medium, utm_campaign, views)
+			SELECT (created_at AT TIME ZONE 'UTC')::date, content_type, content_key,
+				referrer_host, utm_source, utm_medium, utm_campaign, COUNT(*)
+			FROM page_views
+			WHERE created_at >= ? AND event = ?
+			GROUP BY 1, 2, 3, 4, 5, 6, 7
+			ON CONFLICT (day, content_type, content_key, referrer_host, utm_source, utm_medium, utm_campaign)
+			DO UPDATE SET views = EXCLUDED.views`, since, model.AnalyticsEventView).Error
+	})
+}
+
+func (r *analyticsRepository) PurgeViewsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&model.PageView{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *analyticsRepository) TopContent(ctx context.Context, q model.AnalyticsQuery) ([]model.ContentStat, error) {
+	var stats []model.ContentStat
+	err := r.daily(ctx, q).
+		Select("content_type, content_key, SUM(views) AS views, SUM(visitors) AS visitors").
+		Group("content_type, content_key").
+		Order("views DESC, content_key ASC").
+		Limit(q.Limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepository) TimeSeries(ctx context.Context, q model.AnalyticsQuery) ([]model.AnalyticsPoint, error) {
+	var points []model.AnalyticsPoint
+	err := r.daily(ctx, q).
+		Select("TO_CHAR(day, 'YYYY-MM-DD') AS day, SUM(views) AS views, SUM(visitors) AS visitors").
+		Group("analytics_daily.day").
+		Order("analytics_daily.day ASC").
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *analyticsRepository) Referrers(ctx context.Context, q model.AnalyticsQuery) ([]model.ReferrerStat, error) {
+	columns := "referrer_host"
+	if q.ByUTM {
+		columns = "utm_source, utm_medium, utm_campaign"
+	}
+
+	query := r.db.WithContext(ctx).Table("analytics_referrers_daily").Where("day BETWEEN ? AND ?", q.From, q.To)
+	if q.ContentType != "" {
+		query = query.Where("content_type = ?", q.ContentType)
+	}
+	if q.ContentKey != "" {
+		query = query.Where("content_key = ?", q.ContentKey)
+	}
+
+	var stats []model.ReferrerStat
+	err := query.
+		Select(columns + ", SUM(views) AS views").
+		Group(columns).
+		Order("views DESC").
+		Limit(q.Limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *analyticsRepository) daily(ctx context.Context, q model.AnalyticsQuery) *gorm.DB {
+	query := r.db.WithContext(ctx).Table("analytics_daily").Where("day BETWEEN ? AND ? AND event = ?", q.From, q.To, q.Event)
+	if q.ContentType != "" {
+		query = query.Where("content_type = ?", q.ContentType)
+	}
+	if q.ContentKey != "" {
+		query = query.Where("content_key = ?", q.ContentKey)
+	}
+	return query
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+)
+
+const archiveBatchSize = 100
+
+// errDryRun rolls back the import transaction after a dry run
+var errDryRun = errors.New("dry run")
+
+type archiveRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) ArchiveRepository {
+	return &archiveRepository{db: db}
+}
+
+func (r *archiveRepository) EachProjectType(ctx context.Context, fn func(projectType *model.ProjectType) error) error {
+	var projectTypes []model.ProjectType
+	return r.db.WithContext(ctx).Order("id ASC").FindInBatches(&projectTypes, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range projectTypes {
+			if err := fn(&projectTypes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *archiveRepository) EachSeries(ctx context.Context, fn func(series *model.Series) error) error {
+	var series []model.Series
+	return r.db.WithContext(ctx).Order("id ASC").FindInBatches(&series, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range series {
+			if err := fn(&series[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *archiveRepository) EachPost(ctx context.Context, fn func(post *model.BlogPost) error) error {
+	var posts []model.BlogPost
+	return r.db.WithContext(ctx).Preload("Tags").Order("id ASC").FindInBatches(&posts, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range posts {
+			if err := fn(&posts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *archiveRepository) EachProject(ctx context.Context, fn func(project *model.PortfolioProject) error) error {
+	var projects []model.PortfolioProject
+	return preloadProject(r.db.WithContext(ctx)).Order("id ASC").FindInBatches(&projects, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range projects {
+			projects[i].Technologies = technologyNames(projects[i].TechnologyRecords)
+			if err := fn(&projects[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *archiveRepository) EachContact(ctx context.Context, fn func(submission *model.ContactSubmission) error) error {
+	var submissions []model.ContactSubmission
+	return r.db.WithContext(ctx).Order("id ASC").FindInBatches(&submissions, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range submissions {
+			if err := fn(&submissions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *archiveRepository) EachUser(ctx context.Context, fn func(user *model.User) error) error {
+	var users []model.User
+	return r.db.WithContext(ctx).Order("id ASC").FindInBatches(&users, archiveBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range users {
+			if err := fn(&users[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// Import runs fn in one transaction that is rolled back when dryRun is set.
+// Every upsert runs in its own savepoint, so one bad record does not abort the rest.
+func (r *archiveRepository) Import(ctx context.Context, dryRun bool, fn func(importer ArchiveImporter) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fn(&archiveImporter{tx: tx}); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
+
+type archiveImporter struct {
+	tx *gorm.DB
+}
+
+func (im *archiveImporter) UpsertProjectType(projectType *model.ProjectType) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		var existing model.ProjectType
+		err := tx.Unscoped().Where("slug = ?", projectType.Slug).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			projectType.ID = 0
+			return tx.Create(projectType).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		projectType.ID = existing.ID
+		projectType.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Select("name", "description", "sort_order", "icon", "deleted_at").Updates(projectType).Error
+	})
+	return created, err
+}
+
+func (im *archiveImporter) UpsertSeries(series *model.Series) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		var existing model.Series
+		err := tx.Unscoped().Where("slug = ?", series.Slug).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			series.ID = 0
+			return tx.Create(series).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		series.ID = existing.ID
+		series.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Select("title", "description", "deleted_at").Updates(series).Error
+	})
+	return created, err
+}
+
+// SeriesIDBySlug resolves a series imported earlier in the same transaction
+func (im *archiveImporter) SeriesIDBySlug(slug string) (*uint, error) {
+	var series model.Series
+	err := im.tx.Where("slug = ?", slug).First(&series).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &series.ID, nil
+}
+
+func (im *archiveImporter) UpsertPost(post *model.BlogPost) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, post.Tags)
+		if err != nil {
+			return err
+		}
+		post.Tags = nil
+
+		var existing model.BlogPost
+		err = tx.Unscoped().Where("slug = ?", post.Slug).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			post.ID = 0
+			if err := tx.Omit("Tags").Create(post).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			post.ID = existing.ID
+			post.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Omit("Tags").Save(post).Error; err != nil {
+				return err
+			}
+		}
+
+		post.Tags = tags
+		return tx.Model(post).Association("Tags").Replace(tags)
+	})
+	return created, err
+}
+
+// UpsertProject matches projects by title and type, as projects have no slug
+func (im *archiveImporter) UpsertProject(project *model.PortfolioProject) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		technologies, err := resolveTechnologies(tx, project.Technologies)
+		if err != nil {
+			return err
+		}
+		project.TechnologyRecords = nil
+		project.Technologies = technologyNames(technologies)
+		if project.Music != nil {
+			resetMusicIDs(project.Music)
+		}
+
+		var existing model.PortfolioProject
+		err = tx.Where("LOWER(title) = ? AND project_type = ?", strings.ToLower(project.Title), project.ProjectType).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			project.ID = 0
+			if err := tx.Create(project).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			project.ID = existing.ID
+			if err := deleteMusic(tx, project.ID); err != nil {
+				return err
+			}
+			if project.Music != nil {
+				project.Music.PortfolioProjectID = project.ID
+			}
+			if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Omit("TechnologyRecords").Save(project).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(project).Association("TechnologyRecords").Replace(technologies)
+	})
+	return created, err
+}
+
+// UpsertContact matches submissions by sender and submission time
+func (im *archiveImporter) UpsertContact(submission *model.ContactSubmission) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		var existing model.ContactSubmission
+		err := tx.Where("email = ? AND created_at = ?", submission.Email, submission.CreatedAt).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			submission.ID = 0
+			return tx.Create(submission).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		submission.ID = existing.ID
+		return tx.Save(submission).Error
+	})
+	return created, err
+}
+
+// UpsertUser matches users by username; the password hash is only written when present
+func (im *archiveImporter) UpsertUser(user *model.User) (bool, error) {
+	var created bool
+	err := im.tx.Transaction(func(tx *gorm.DB) error {
+		var existing model.User
+		err := tx.Unscoped().Where("username = ?", user.Username).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			user.ID = 0
+			if user.PasswordHash == "" {
+				// Not a valid bcrypt hash, so the account cannot log in until its password is reset
+				user.PasswordHash = "!"
+				user.Active = false
+			}
+			active := user.Active
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+			// Create leaves out a false Active, so the column default would turn it back on
+			if !active {
+				user.Active = false
+				return tx.Model(user).Update("active", false).Error
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		user.ID = existing.ID
+		user.DeletedAt = gorm.DeletedAt{}
+		columns := []string{"email", "role", "active", "deleted_at"}
+		if user.PasswordHash != "" {
+			columns = append(columns, "password_hash")
+		}
+		return tx.Unscoped().Select(columns).Updates(user).Error
+	})
+	return created, err
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
@@ -16,17 +19,33 @@ func NewBlogRepository(db *gorm.DB) BlogRepository {
 	return &blogRepository{db: db}
 }
 
-func (r *blogRepository) GetBaseQuery() *gorm.DB {
-	return r.db.Model(&model.BlogPost{})
+func (r *blogRepository) GetBaseQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.BlogPost{})
 }
 
-func (r *blogRepository) Create(post *model.BlogPost) error {
-	return r.db.Create(post).Error
+func (r *blogRepository) Create(ctx context.Context, post *model.BlogPost, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, post.Tags)
+		if err != nil {
+			return err
+		}
+
+		post.Tags = nil
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+
+		post.Tags = tags
+		if err := tx.Model(post).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-func (r *blogRepository) GetByID(id uint) (*model.BlogPost, error) {
+func (r *blogRepository) GetByID(ctx context.Context, id uint) (*model.BlogPost, error) {
 	var post model.BlogPost
-	err := r.db.Preload("Tags").First(&post, id).Error
+	err := r.db.WithContext(ctx).Preload("Tags").First(&post, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -36,39 +55,198 @@ func (r *blogRepository) GetByID(id uint) (*model.BlogPost, error) {
 	return &post, nil
 }
 
-func (r *blogRepository) GetAll(page, pageSize int) (*paging.Paginator, error) {
+func (r *blogRepository) GetAll(ctx context.Context, page, pageSize int, sort string) (*paging.Paginator, error) {
+	db := r.db.WithContext(ctx)
+	var posts []model.BlogPost
+	orderBy := []string{"created_at DESC"}
+	if sort == model.BlogSortReactions {
+		orderBy = []string{reactionTotalColumn(model.ReactionContentPost, "blog_posts") + " DESC", "created_at DESC"}
+	}
+
+	pagingParam := &paging.Param{
+		DB:      db.Preload("Tags"),
+		Page:    page,
+		Limit:   pageSize,
+		OrderBy: orderBy,
+	}
+
+	paginator := paging.Paging(pagingParam, &posts)
+	if err := fillCommentCounts(db, posts); err != nil {
+		return nil, err
+	}
+	if err := fillPostReactions(db, posts); err != nil {
+		return nil, err
+	}
+	return paginator, nil
+}
+
+// GetPublished lists the posts that are published and whose PublishAt has passed
+func (r *blogRepository) GetPublished(ctx context.Context, page, pageSize int, now time.Time) (*paging.Paginator, error) {
+	db := r.db.WithContext(ctx)
 	var posts []model.BlogPost
 	pagingParam := &paging.Param{
-		DB:      r.db.Preload("Tags"),
+		DB:      db.Preload("Tags").Where("published = ? AND publish_at <= ?", true, now),
 		Page:    page,
 		Limit:   pageSize,
 		OrderBy: []string{"created_at DESC"},
 	}
 
 	paginator := paging.Paging(pagingParam, &posts)
+	if err := fillCommentCounts(db, posts); err != nil {
+		return nil, err
+	}
+	if err := fillPostReactions(db, posts); err != nil {
+		return nil, err
+	}
 	return paginator, nil
 }
 
-func (r *blogRepository) GetBySlug(slug string) (*model.BlogPost, error) {
+// GetRelatedCandidates lists the visible posts with their tags but without their content
+func (r *blogRepository) GetRelatedCandidates(ctx context.Context, now time.Time) ([]model.BlogPost, error) {
+	var posts []model.BlogPost
+	err := r.db.WithContext(ctx).Preload("Tags").
+		Select("id", "title", "slug", "summary", "image_url", "publish_at").
+		Where("published = ? AND publish_at <= ?", true, now).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *blogRepository) GetRelatedPins(ctx context.Context, postID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.RelatedPin{}).
+		Where("post_id = ?", postID).
+		Order("position ASC").
+		Pluck("related_post_id", &ids).Error
+	return ids, err
+}
+
+func (r *blogRepository) SetRelatedPins(ctx context.Context, postID uint, relatedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", postID).Delete(&model.RelatedPin{}).Error; err != nil {
+			return err
+		}
+		if len(relatedIDs) == 0 {
+			return nil
+		}
+
+		pins := make([]model.RelatedPin, len(relatedIDs))
+		for i, id := range relatedIDs {
+			pins[i] = model.RelatedPin{PostID: postID, RelatedPostID: id, Position: i + 1}
+		}
+		return tx.Create(&pins).Error
+	})
+}
+
+func (r *blogRepository) CountByIDs(ctx context.Context, ids []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.BlogPost{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+func (r *blogRepository) GetBySlug(ctx context.Context, slug string) (*model.BlogPost, error) {
+	db := r.db.WithContext(ctx)
 	var post model.BlogPost
-	err := r.db.Preload("Tags").Where("slug = ?", slug).First(&post).Error
+	err := db.Preload("Tags").Where("slug = ?", slug).First(&post).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &post, nil
+	posts := []model.BlogPost{post}
+	if err := fillCommentCounts(db, posts); err != nil {
+		return nil, err
+	}
+	if err := fillPostReactions(db, posts); err != nil {
+		return nil, err
+	}
+	return &posts[0], nil
+}
+
+func (r *blogRepository) Update(ctx context.Context, post *model.BlogPost, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, post.Tags)
+		if err != nil {
+			return err
+		}
+
+		post.Tags = nil
+		if err := tx.Save(post).Error; err != nil {
+			return err
+		}
+
+		post.Tags = tags
+		if err := tx.Model(post).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
+}
+
+func (r *blogRepository) Delete(ctx context.Context, id uint, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.BlogPost{}, id).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-func (r *blogRepository) Update(post *model.BlogPost) error {
-	if err := r.db.Model(post).Association("Tags").Clear(); err != nil {
+// fillCommentCounts sets CommentCount to the number of approved comments of each post
+func fillCommentCounts(db *gorm.DB, posts []model.BlogPost) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+	}
+
+	var rows []struct {
+		PostID uint
+		Count  int64
+	}
+	err := db.Model(&model.Comment{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("post_id IN ? AND status = ?", ids, model.CommentStatusApproved).
+		Group("post_id").
+		Scan(&rows).Error
+	if err != nil {
 		return err
 	}
 
-	return r.db.Session(&gorm.Session{FullSaveAssociations: true}).Save(post).Error
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.PostID] = row.Count
+	}
+	for i := range posts {
+		posts[i].CommentCount = counts[posts[i].ID]
+	}
+	return nil
 }
 
-func (r *blogRepository) Delete(id uint) error {
-	return r.db.Delete(&model.BlogPost{}, id).Error
+// resolveTags maps tags onto existing rows by name, creating the missing ones,
+// so posts sharing a tag never trip over its unique index
+func resolveTags(db *gorm.DB, tags []model.Tag) ([]model.Tag, error) {
+	resolved := make([]model.Tag, 0, len(tags))
+	seen := make(map[uint]bool)
+
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag.Name)
+		if name == "" {
+			continue
+		}
+
+		record := model.Tag{Name: name}
+		if err := db.Where(model.Tag{Name: name}).FirstOrCreate(&record).Error; err != nil {
+			return nil, err
+		}
+
+		if !seen[record.ID] {
+			seen[record.ID] = true
+			resolved = append(resolved, record)
+		}
+	}
+	return resolved, nil
 }
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+	"gorm.io/gorm"
+)
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	err := r.db.WithContext(ctx).First(&comment, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetApproved lists the approved comments of a post, oldest first
+func (r *commentRepository) GetApproved(ctx context.Context, postID uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	err := r.db.WithContext(ctx).Where("post_id = ? AND status = ?", postID, model.CommentStatusApproved).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) GetAll(ctx context.Context, filter model.CommentFilter, page, pageSize int) (*paging.Paginator, error) {
+	var comments []model.Comment
+
+	db := r.db.WithContext(ctx)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.PostID != 0 {
+		db = db.Where("post_id = ?", filter.PostID)
+	}
+
+	pagingParam := &paging.Param{
+		DB:      db,
+		Page:    page,
+		Limit:   pageSize,
+		OrderBy: []string{"created_at DESC"},
+	}
+
+	paginator := paging.Paging(pagingParam, &comments)
+	return paginator, nil
+}
+
+// CountSince counts the comments sent from an address after since, whatever their status
+func (r *commentRepository) CountSince(ctx context.Context, ipHash string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("ip_hash = ? AND created_at > ?", ipHash, since).
+		Count(&count).Error
+	return count, err
+}
+
+// ExistsDuplicate reports whether the same author already sent this text to the post after since
+func (r *commentRepository) ExistsDuplicate(ctx context.Context, postID uint, email, content string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("post_id = ? AND LOWER(author_email) = LOWER(?) AND content = ? AND created_at > ?", postID, email, content, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasApproved reports whether an author has had a comment approved before
+func (r *commentRepository) HasApproved(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("LOWER(author_email) = LOWER(?) AND status = ?", email, model.CommentStatusApproved).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commentRepository) SetStatus(ctx context.Context, ids []uint, status string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Comment{}).Where("id IN ?", ids).Update("status", status)
+	return result.RowsAffected, result.Error
+}
+
+// Delete removes a comment for good; its replies go with it through the foreign key
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.Comment{}, id).Error
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
 	"gorm.io/gorm"
@@ -14,19 +15,24 @@ func NewContactRepository(db *gorm.DB) ContactRepository {
 	return &contactRepository{db: db}
 }
 
-func (r *contactRepository) GetBaseQuery() *gorm.DB {
-	return r.db.Model(&model.ContactSubmission{})
+func (r *contactRepository) GetBaseQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ContactSubmission{})
 }
 
-func (r *contactRepository) Create(submission *model.ContactSubmission) error {
-	return r.db.Create(submission).Error
+func (r *contactRepository) Create(ctx context.Context, submission *model.ContactSubmission, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(submission).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-func (r *contactRepository) GetAll(page, pageSize int) (*paging.Paginator, error) {
+func (r *contactRepository) GetAll(ctx context.Context, page, pageSize int) (*paging.Paginator, error) {
 	var submissions []model.ContactSubmission
 
 	pagingParam := &paging.Param{
-		DB:      r.db,
+		DB:      r.db.WithContext(ctx),
 		Page:    page,
 		Limit:   pageSize,
 		OrderBy: []string{"created_at DESC"},
@@ -36,10 +42,10 @@ func (r *contactRepository) GetAll(page, pageSize int) (*paging.Paginator, error
 	return paginator, nil
 }
 
-func (r *contactRepository) MarkAsRead(id uint) error {
-	return r.db.Model(&model.ContactSubmission{}).Where("id = ?", id).Update("read", true).Error
+func (r *contactRepository) MarkAsRead(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.ContactSubmission{}).Where("id = ?", id).Update("read", true).Error
 }
 
-func (r *contactRepository) Delete(id uint) error {
-	return r.db.Delete(&model.ContactSubmission{}, id).Error
+func (r *contactRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.ContactSubmission{}, id).Error
 }
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lutestringamend/perwebbe/internal/migration"
+	"gorm.io/gorm"
+)
+
+type healthRepository struct {
+	db *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PendingMigrations counts the embedded migrations the database has not applied
+func (r *healthRepository) PendingMigrations(ctx context.Context) (int, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return 0, err
+	}
+
+	migrator, err := migration.NewMigrator(sqlDB)
+	if err != nil {
+		return 0, err
+	}
+	return migrator.Pending(ctx)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *model.Job) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uint) (*model.Job, error) {
+	var job model.Job
+	err := r.db.WithContext(ctx).First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) GetAll(ctx context.Context, filter model.JobFilter, page, pageSize int) (*paging.Paginator, error) {
+	var jobs []model.Job
+
+	query := r.db.WithContext(ctx).Model(&model.Job{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+
+	pagingParam := &paging.Param{
+		DB:      query,
+		Page:    page,
+		Limit:   pageSize,
+		OrderBy: []string{"id DESC"},
+	}
+
+	paginator := paging.Paging(pagingParam, &jobs)
+	return paginator, nil
+}
+
+// CountByStatus returns the number of jobs in each state
+func (r *jobRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.Job{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{
+		model.JobPending:   0,
+		model.JobRunning:   0,
+		model.JobSucceeded: 0,
+		model.JobDead:      0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ClaimNext picks the oldest due job of one of the given kinds and marks it
+// running until now+lease. Running jobs whose lease ran out belong to a worker
+// that died and are picked up again. Rows locked by another worker are skipped.
+func (r *jobRepository) ClaimNext(ctx context.Context, kinds []string, now time.Time, lease time.Duration) (*model.Job, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	var jobs []model.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("kind IN ?", kinds).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", model.JobPending, now, model.JobRunning, now).
+			Order("run_at ASC, id ASC").
+			Limit(1).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		job := &jobs[0]
+		lockedUntil := now.Add(lease)
+		job.Status = model.JobRunning
+		job.Attempts++
+		job.LockedUntil = &lockedUntil
+		job.StartedAt = &now
+		return tx.Model(job).Select("status", "attempts", "locked_until", "started_at").Updates(job).Error
+	})
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// Update saves the state of a job after it ran or was changed by an admin
+func (r *jobRepository) Update(ctx context.Context, job *model.Job) error {
+	return r.db.WithContext(ctx).Model(job).
+		Select("status", "attempts", "run_at", "locked_until", "started_at", "finished_at", "last_error").
+		Updates(job).Error
+}
+
+func (r *jobRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Job{}, id).Error
+}
+
+// DeleteSucceeded removes jobs that succeeded before the given time
+func (r *jobRepository) DeleteSucceeded(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("status = ? AND finished_at < ?", model.JobSucceeded, before).Delete(&model.Job{})
+	return result.RowsAffected, result.Error
+}
+
+// SaveSchedule stores a recurring job. The next run is only replaced when the
+// schedule is new or its spec or kind changed, so restarts keep the timing.
+func (r *jobRepository) SaveSchedule(ctx context.Context, schedule *model.JobSchedule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.JobSchedule
+		err := tx.Where("name = ?", schedule.Name).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(schedule).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if existing.Spec == schedule.Spec && existing.Kind == schedule.Kind {
+			*schedule = existing
+			return nil
+		}
+		return tx.Model(schedule).Select("spec", "kind", "next_run_at").Updates(schedule).Error
+	})
+}
+
+func (r *jobRepository) GetSchedules(ctx context.Context) ([]model.JobSchedule, error) {
+	var schedules []model.JobSchedule
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// FireSchedules queues the job fire returns for each due schedule among names
+// and moves the schedule to its next run, in one transaction. Schedules locked
+// by another instance are skipped, so every run is queued once, and no job is
+// queued while the previous one of the same schedule is still pending or running.
+func (r *jobRepository) FireSchedules(ctx context.Context, names []string, now time.Time, fire func(schedule *model.JobSchedule) (*model.Job, time.Time)) (int, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	var fired int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var schedules []model.JobSchedule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("name IN ? AND next_run_at <= ?", names, now).
+			Find(&schedules).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range schedules {
+			schedule := &schedules[i]
+			var unfinished int64
+			err := tx.Model(&model.Job{}).
+				Where("schedule = ? AND status IN ?", schedule.Name, []string{model.JobPending, model.JobRunning}).
+				Count(&unfinished).Error
+			if err != nil {
+				return err
+			}
+
+			job, next := fire(schedule)
+			if unfinished == 0 {
+				if err := tx.Create(job).Error; err != nil {
+					return err
+				}
+				fired++
+			}
+
+			schedule.NextRunAt = next
+			schedule.LastRunAt = &now
+			if err := tx.Model(schedule).Select("next_run_at", "last_run_at").Updates(schedule).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return fired, err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+	"gorm.io/gorm"
+)
+
+type newsletterRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsletterRepository(db *gorm.DB) NewsletterRepository {
+	return &newsletterRepository{db: db}
+}
+
+func (r *newsletterRepository) CreateSubscriber(ctx context.Context, subscriber *model.Subscriber) error {
+	return r.db.WithContext(ctx).Create(subscriber).Error
+}
+
+func (r *newsletterRepository) GetSubscriberByID(ctx context.Context, id uint) (*model.Subscriber, error) {
+	return r.findSubscriber(ctx, "id = ?", id)
+}
+
+func (r *newsletterRepository) GetSubscriberByEmail(ctx context.Context, email string) (*model.Subscriber, error) {
+	return r.findSubscriber(ctx, "LOWER(email) = LOWER(?)", email)
+}
+
+func (r *newsletterRepository) GetSubscriberByConfirmToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return r.findSubscriber(ctx, "confirm_token = ?", token)
+}
+
+func (r *newsletterRepository) GetSubscriberByUnsubscribeToken(ctx context.Context, token string) (*model.Subscriber, error) {
+	return r.findSubscriber(ctx, "unsubscribe_token = ?", token)
+}
+
+func (r *newsletterRepository) findSubscriber(ctx context.Context, query string, arg interface{}) (*model.Subscriber, error) {
+	var subscriber model.Subscriber
+	err := r.db.WithContext(ctx).Where(query, arg).First(&subscriber).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscriber, nil
+}
+
+func (r *newsletterRepository) GetSubscribers(ctx context.Context, status string, page, pageSize int) (*paging.Paginator, error) {
+	var subscribers []model.Subscriber
+
+	db := r.db.WithContext(ctx)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	pagingParam := &paging.Param{
+		DB:      db,
+		Page:    page,
+		Limit:   pageSize,
+		OrderBy: []string{"created_at DESC"},
+	}
+
+	paginator := paging.Paging(pagingParam, &subscribers)
+	return paginator, nil
+}
+
+// NextActiveSubscribers returns the next batch of active subscribers after afterID
+func (r *newsletterRepository) NextActiveSubscribers(ctx context.Context, afterID uint, limit int) ([]model.Subscriber, error) {
+	var subscribers []model.Subscriber
+	err := r.db.WithContext(ctx).Where("status = ? AND id > ?", model.SubscriberActive, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&subscribers).Error
+	return subscribers, err
+}
+
+func (r *newsletterRepository) UpdateSubscriber(ctx context.Context, subscriber *model.Subscriber) error {
+	return r.db.WithContext(ctx).Save(subscriber).Error
+}
+
+// DeleteSubscriber removes the row for good, so the address can subscribe again
+func (r *newsletterRepository) DeleteSubscriber(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.Subscriber{}, id).Error
+}
+
+// GetDigestPosts lists the visible posts published after since that no issue has included yet
+func (r *newsletterRepository) GetDigestPosts(ctx context.Context, since, now time.Time) ([]model.BlogPost, error) {
+	var posts []model.BlogPost
+	err := r.db.WithContext(ctx).
+		Where("published = ? AND publish_at > ? AND publish_at <= ?", true, since, now).
+		Where("id NOT IN (SELECT blog_post_id FROM newsletter_issue_posts)").
+		Order("publish_at ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+// CreateIssue stores the issue and links its posts without touching the posts themselves
+func (r *newsletterRepository) CreateIssue(ctx context.Context, issue *model.NewsletterIssue) error {
+	return r.db.WithContext(ctx).Omit("Posts.*").Create(issue).Error
+}
+
+// GetOpenIssue returns the issue that is still being sent, if any
+func (r *newsletterRepository) GetOpenIssue(ctx context.Context) (*model.NewsletterIssue, error) {
+	var issue model.NewsletterIssue
+	err := r.db.WithContext(ctx).Preload("Posts").Where("status = ?", model.NewsletterIssueSending).Order("id ASC").First(&issue).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// UpdateIssueProgress saves the sending cursor, counters and status of an issue
+func (r *newsletterRepository) UpdateIssueProgress(ctx context.Context, issue *model.NewsletterIssue) error {
+	return r.db.WithContext(ctx).Model(issue).
+		Select("status", "last_subscriber_id", "sent_count", "failed_count", "completed_at").
+		Updates(issue).Error
+}
+
+func (r *newsletterRepository) GetIssues(ctx context.Context, page, pageSize int) (*paging.Paginator, error) {
+	var issues []model.NewsletterIssue
+	pagingParam := &paging.Param{
+		DB: r.db.WithContext(ctx).Preload("Posts", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title", "slug", "summary", "image_url", "publish_at")
+		}),
+		Page:    page,
+		Limit:   pageSize,
+		OrderBy: []string{"created_at DESC"},
+	}
+
+	paginator := paging.Paging(pagingParam, &issues)
+	return paginator, nil
+}
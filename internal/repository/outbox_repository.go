@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Create stores events that do not come with a write, such as a scheduled post going live
+func (r *outboxRepository) Create(ctx context.Context, events ...model.DomainEvent) error {
+	return writeOutbox(r.db.WithContext(ctx), events)
+}
+
+// ClaimDue picks up to limit pending events that are due, oldest first, and
+// pushes their next attempt back by lease so other workers skip them.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxPending, now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+		}
+		return tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return events, err
+}
+
+// Update saves the outcome of passing an event to the subscribers
+func (r *outboxRepository) Update(ctx context.Context, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Model(event).
+		Select("status", "attempts", "next_attempt_at", "processed_at", "handled", "error").
+		Updates(event).Error
+}
+
+// DeleteProcessed removes events that were processed before the given time
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("status = ? AND processed_at < ?", model.OutboxProcessed, before).Delete(&model.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// writeOutbox stores events in tx, so they are only kept when the write that caused them commits
+func writeOutbox(tx *gorm.DB, events []model.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]model.OutboxEvent, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = model.OutboxEvent{
+			Event:         event.EventName(),
+			Payload:       string(payload),
+			Status:        model.OutboxPending,
+			NextAttemptAt: now,
+		}
+	}
+	return tx.Create(&rows).Error
+}
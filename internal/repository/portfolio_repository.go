@@ -1,8 +1,10 @@
 package repository
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
@@ -17,22 +19,30 @@ func NewPortfolioRepository(db *gorm.DB) PortfolioRepository {
 	return &portfolioRepository{db: db}
 }
 
-func (r *portfolioRepository) GetBaseQuery() *gorm.DB {
-	return r.db.Model(&model.PortfolioProject{})
+func (r *portfolioRepository) GetBaseQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.PortfolioProject{})
 }
 
-func (r *portfolioRepository) Create(project *model.PortfolioProject) error {
-	techJSON, err := json.Marshal(project.Technologies)
-	if err != nil {
-		return err
-	}
-	project.TechJSON = string(techJSON)
-	return r.db.Create(project).Error
+func (r *portfolioRepository) Create(ctx context.Context, project *model.PortfolioProject, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		technologies, err := resolveTechnologies(tx, project.Technologies)
+		if err != nil {
+			return err
+		}
+		project.TechnologyRecords = technologies
+		project.Technologies = technologyNames(technologies)
+
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-func (r *portfolioRepository) GetByID(id uint) (*model.PortfolioProject, error) {
+func (r *portfolioRepository) GetByID(ctx context.Context, id uint) (*model.PortfolioProject, error) {
+	db := r.db.WithContext(ctx)
 	var project model.PortfolioProject
-	err := r.db.First(&project, id).Error
+	err := preloadProject(db).First(&project, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -40,50 +50,239 @@ func (r *portfolioRepository) GetByID(id uint) (*model.PortfolioProject, error)
 		return nil, err
 	}
 
-	if project.TechJSON != "" {
-		if err := json.Unmarshal([]byte(project.TechJSON), &project.Technologies); err != nil {
-			return nil, err
-		}
+	project.Technologies = technologyNames(project.TechnologyRecords)
+	projects := []model.PortfolioProject{project}
+	if err := fillProjectReactions(db, projects); err != nil {
+		return nil, err
 	}
-	return &project, nil
+	return &projects[0], nil
 }
 
-func (r *portfolioRepository) GetAll(projectType string, page, pageSize int) (*paging.Paginator, error) {
+func (r *portfolioRepository) GetAll(ctx context.Context, filter model.ProjectFilter, page, pageSize int) (*paging.Paginator, error) {
+	db := r.db.WithContext(ctx)
 	var projects []model.PortfolioProject
-	query := r.db.Model(model.PortfolioProject{})
+	query := preloadProject(db.Model(model.PortfolioProject{}))
 
-	if projectType != "" {
-		query = query.Where("project_type = ?", projectType)
+	if filter.ProjectType != "" {
+		query = query.Where("project_type = ?", filter.ProjectType)
 	}
+	if filter.Featured != nil {
+		query = query.Where("featured = ?", *filter.Featured)
+	}
+	if len(filter.Technologies) > 0 {
+		var err error
+		query, err = r.filterByTechnologies(ctx, query, filter.Technologies)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	pagingParam := &paging.Param{
 		DB:      query,
 		Page:    page,
 		Limit:   pageSize,
-		OrderBy: []string{"created_at DESC"},
+		OrderBy: projectOrder(filter),
 	}
 
 	paginator := paging.Paging(pagingParam, &projects)
 
 	for i := range projects {
-		if projects[i].TechJSON != "" {
-			if err := json.Unmarshal([]byte(projects[i].TechJSON), &projects[i].Technologies); err != nil {
-				return nil, err
+		projects[i].Technologies = technologyNames(projects[i].TechnologyRecords)
+	}
+	if err := fillProjectReactions(db, projects); err != nil {
+		return nil, err
+	}
+
+	return paginator, nil
+}
+
+func (r *portfolioRepository) Update(ctx context.Context, project *model.PortfolioProject, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		technologies, err := resolveTechnologies(tx, project.Technologies)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(project).Association("TechnologyRecords").Replace(technologies); err != nil {
+			return err
+		}
+		project.TechnologyRecords = technologies
+		project.Technologies = technologyNames(technologies)
+
+		// The release is rewritten as a whole, so drop the previous tracks,
+		// credits and links rather than trying to diff them
+		if err := deleteMusic(tx, project.ID); err != nil {
+			return err
+		}
+		if project.Music != nil {
+			resetMusicIDs(project.Music)
+			project.Music.PortfolioProjectID = project.ID
+		}
+		if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Omit("TechnologyRecords").Save(project).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
+}
+
+// Reorder numbers the listed projects first and then the rest in their current
+// manual order, so a partial list never leaves two projects on one position
+func (r *portfolioRepository) Reorder(ctx context.Context, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rest := tx.Model(&model.PortfolioProject{}).Order("sort_order ASC, created_at DESC")
+		if len(ids) > 0 {
+			rest = rest.Where("id NOT IN ?", ids)
+		}
+		var unlisted []uint
+		if err := rest.Pluck("id", &unlisted).Error; err != nil {
+			return err
+		}
+
+		for position, id := range append(ids, unlisted...) {
+			err := tx.Model(&model.PortfolioProject{}).Where("id = ?", id).Update("sort_order", position+1).Error
+			if err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+}
+
+func (r *portfolioRepository) CountByIDs(ctx context.Context, ids []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PortfolioProject{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+func (r *portfolioRepository) GetLinkedToRepositories(ctx context.Context) ([]model.PortfolioProject, error) {
+	var projects []model.PortfolioProject
+	err := r.db.WithContext(ctx).Select("id", "repo_url").Where("repo_url <> ''").Order("id ASC").Find(&projects).Error
+	return projects, err
+}
+
+func (r *portfolioRepository) UpdateRepositoryStats(ctx context.Context, id uint, stars int, lastCommitAt *time.Time, syncedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.PortfolioProject{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"stars":          stars,
+		"last_commit_at": lastCommitAt,
+		"repo_synced_at": syncedAt,
+	}).Error
+}
+
+func (r *portfolioRepository) Delete(ctx context.Context, id uint, events ...model.DomainEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteMusic(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.PortfolioProject{}, id).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
+}
+
+// filterByTechnologies keeps only the projects that use every requested technology
+func (r *portfolioRepository) filterByTechnologies(ctx context.Context, query *gorm.DB, names []string) (*gorm.DB, error) {
+	db := r.db.WithContext(ctx)
+
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		technology, err := findTechnology(db, name)
+		if err != nil {
+			return nil, err
+		}
+		if technology == nil {
+			return query.Where("1 = 0"), nil
+		}
+		ids = append(ids, technology.ID)
+	}
+	if len(ids) == 0 {
+		return query, nil
 	}
 
-	return paginator, nil
+	matching := db.Table("project_technologies").
+		Select("portfolio_project_id").
+		Where("technology_id IN ?", ids).
+		Group("portfolio_project_id").
+		Having("COUNT(DISTINCT technology_id) = ?", len(ids))
+	return query.Where("id IN (?)", matching), nil
 }
 
-func (r *portfolioRepository) Update(project *model.PortfolioProject) error {
-	techJSON, err := json.Marshal(project.Technologies)
-	if err != nil {
+// projectOrder translates the requested sort into ORDER BY clauses, with
+// created_at as the tie breaker so pages stay stable
+func projectOrder(filter model.ProjectFilter) []string {
+	column, descending := "created_at", true
+	switch filter.Sort {
+	case model.ProjectSortStartDate:
+		column = "start_date"
+	case model.ProjectSortEndDate:
+		column = "end_date"
+	case model.ProjectSortTitle:
+		column, descending = "title", false
+	case model.ProjectSortManual:
+		column, descending = "sort_order", false
+	case model.ProjectSortReactions:
+		column = reactionTotalColumn(model.ReactionContentProject, "portfolio_projects")
+	}
+	if filter.Descending != nil {
+		descending = *filter.Descending
+	}
+
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+
+	orderBy := []string{column + direction}
+	if filter.Sort == model.ProjectSortManual {
+		orderBy = []string{"featured DESC", column + direction}
+	}
+	if column != "created_at" {
+		orderBy = append(orderBy, "created_at DESC")
+	}
+	return orderBy
+}
+
+func preloadProject(db *gorm.DB) *gorm.DB {
+	return db.Preload("TechnologyRecords", func(db *gorm.DB) *gorm.DB {
+		return db.Order("technologies.name ASC")
+	}).
+		Preload("Music").
+		Preload("Music.Tracks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Music.Credits").
+		Preload("Music.StreamingLinks")
+}
+
+func deleteMusic(tx *gorm.DB, projectID uint) error {
+	var releaseIDs []uint
+	if err := tx.Unscoped().Model(&model.MusicRelease{}).Where("portfolio_project_id = ?", projectID).Pluck("id", &releaseIDs).Error; err != nil {
 		return err
 	}
-	project.TechJSON = string(techJSON)
-	return r.db.Session(&gorm.Session{FullSaveAssociations: true}).Save(project).Error
+	if len(releaseIDs) == 0 {
+		return nil
+	}
+
+	for _, child := range []interface{}{&model.MusicTrack{}, &model.MusicCredit{}, &model.StreamingLink{}} {
+		if err := tx.Unscoped().Where("music_release_id IN ?", releaseIDs).Delete(child).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Delete(&model.MusicRelease{}, releaseIDs).Error
 }
 
-func (r *portfolioRepository) Delete(id uint) error {
-	return r.db.Delete(&model.PortfolioProject{}, id).Error
+func resetMusicIDs(music *model.MusicRelease) {
+	music.ID = 0
+	for i := range music.Tracks {
+		music.Tracks[i].ID = 0
+	}
+	for i := range music.Credits {
+		music.Credits[i].ID = 0
+	}
+	for i := range music.StreamingLinks {
+		music.StreamingLinks[i].ID = 0
+	}
 }
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+)
+
+type projectTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectTypeRepository(db *gorm.DB) ProjectTypeRepository {
+	return &projectTypeRepository{db: db}
+}
+
+func (r *projectTypeRepository) Create(ctx context.Context, projectType *model.ProjectType) error {
+	return r.db.WithContext(ctx).Create(projectType).Error
+}
+
+func (r *projectTypeRepository) GetByID(ctx context.Context, id uint) (*model.ProjectType, error) {
+	var projectType model.ProjectType
+	err := r.db.WithContext(ctx).First(&projectType, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &projectType, nil
+}
+
+func (r *projectTypeRepository) GetBySlug(ctx context.Context, slug string) (*model.ProjectType, error) {
+	var projectType model.ProjectType
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&projectType).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &projectType, nil
+}
+
+func (r *projectTypeRepository) GetAllWithCounts(ctx context.Context) ([]model.ProjectType, error) {
+	var projectTypes []model.ProjectType
+	err := r.db.WithContext(ctx).Model(&model.ProjectType{}).
+		Select("project_types.*, COUNT(portfolio_projects.id) AS project_count").
+		Joins("LEFT JOIN portfolio_projects ON portfolio_projects.project_type = project_types.slug AND portfolio_projects.deleted_at IS NULL").
+		Group("project_types.id").
+		Order("project_types.sort_order ASC, project_types.name ASC").
+		Find(&projectTypes).Error
+	return projectTypes, err
+}
+
+func (r *projectTypeRepository) CountProjects(ctx context.Context, slug string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PortfolioProject{}).Where("project_type = ?", slug).Count(&count).Error
+	return count, err
+}
+
+func (r *projectTypeRepository) Update(ctx context.Context, projectType *model.ProjectType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous model.ProjectType
+		if err := tx.First(&previous, projectType.ID).Error; err != nil {
+			return err
+		}
+
+		// Projects reference their type by slug, so a rename has to follow through
+		if previous.Slug != projectType.Slug {
+			err := tx.Model(&model.PortfolioProject{}).
+				Where("project_type = ?", previous.Slug).
+				Update("project_type", projectType.Slug).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Save(projectType).Error
+	})
+}
+
+func (r *projectTypeRepository) Delete(ctx context.Context, id uint) error {
+	// Hard delete so the slug can be reused; the service refuses to delete types still in use
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.ProjectType{}, id).Error
+}
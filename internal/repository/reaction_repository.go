@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// Add counts a reaction unless the visitor already sent it. The vote and the
+// counter change in one transaction, and both rely on the database to settle
+// concurrent requests: the vote's primary key rejects duplicates and the counter
+// is incremented in place.
+func (r *reactionRepository) Add(ctx context.Context, contentType string, contentID uint, emoji, fingerprint string) (bool, error) {
+	var added bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		vote := model.ReactionVote{ContentType: contentType, ContentID: contentID, Emoji: emoji, Fingerprint: fingerprint}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		added = true
+		counter := model.ReactionCount{ContentType: contentType, ContentID: contentID, Emoji: emoji, Count: 1}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "content_type"}, {Name: "content_id"}, {Name: "emoji"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("reaction_counts.count + 1")}),
+		}).Create(&counter).Error
+	})
+	return added, err
+}
+
+func (r *reactionRepository) Summary(ctx context.Context, contentType string, contentID uint) (*model.ReactionSummary, error) {
+	counts, err := reactionCounts(r.db.WithContext(ctx), contentType, []uint{contentID})
+	if err != nil {
+		return nil, err
+	}
+
+	var summary model.ReactionSummary
+	summary.Reactions, summary.Total = reactionSummary(counts[contentID])
+	return &summary, nil
+}
+
+// reactionCounts loads the counters of several items of one content type at once
+func reactionCounts(db *gorm.DB, contentType string, ids []uint) (map[uint]map[string]int64, error) {
+	counts := make(map[uint]map[string]int64)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []model.ReactionCount
+	err := db.Where("content_type = ? AND content_id IN ? AND count > 0", contentType, ids).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.ContentID] == nil {
+			counts[row.ContentID] = make(map[string]int64)
+		}
+		counts[row.ContentID][row.Emoji] = row.Count
+	}
+	return counts, nil
+}
+
+// reactionTotalColumn is an ORDER BY expression for the total reactions of the rows of table
+func reactionTotalColumn(contentType, table string) string {
+	return fmt.Sprintf("(SELECT COALESCE(SUM(reaction_counts.count), 0) FROM reaction_counts WHERE reaction_counts.content_type = '%s' AND reaction_counts.content_id = %s.id)", contentType, table)
+}
+
+func fillPostReactions(db *gorm.DB, posts []model.BlogPost) error {
+	ids := make([]uint, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+	}
+
+	counts, err := reactionCounts(db, model.ReactionContentPost, ids)
+	if err != nil {
+		return err
+	}
+	for i := range posts {
+		posts[i].Reactions, posts[i].ReactionTotal = reactionSummary(counts[posts[i].ID])
+	}
+	return nil
+}
+
+func fillProjectReactions(db *gorm.DB, projects []model.PortfolioProject) error {
+	ids := make([]uint, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+
+	counts, err := reactionCounts(db, model.ReactionContentProject, ids)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		projects[i].Reactions, projects[i].ReactionTotal = reactionSummary(counts[projects[i].ID])
+	}
+	return nil
+}
+
+func reactionSummary(counts map[string]int64) (map[string]int64, int64) {
+	if counts == nil {
+		counts = map[string]int64{}
+	}
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	return counts, total
+}
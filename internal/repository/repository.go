@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
 	"gorm.io/gorm"
@@ -8,40 +11,208 @@ import (
 
 // BlogRepository defines methods for blog post repository
 type BlogRepository interface {
-	Create(post *model.BlogPost) error
-	GetByID(id uint) (*model.BlogPost, error)
-	GetAll(page, pageSize int) (*paging.Paginator, error)
-	GetBySlug(slug string) (*model.BlogPost, error)
-	Update(post *model.BlogPost) error
-	Delete(id uint) error
-	GetBaseQuery() *gorm.DB
+	Create(ctx context.Context, post *model.BlogPost, events ...model.DomainEvent) error
+	GetByID(ctx context.Context, id uint) (*model.BlogPost, error)
+	GetAll(ctx context.Context, page, pageSize int, sort string) (*paging.Paginator, error)
+	GetBySlug(ctx context.Context, slug string) (*model.BlogPost, error)
+	GetPublished(ctx context.Context, page, pageSize int, now time.Time) (*paging.Paginator, error)
+	GetRelatedCandidates(ctx context.Context, now time.Time) ([]model.BlogPost, error)
+	GetRelatedPins(ctx context.Context, postID uint) ([]uint, error)
+	SetRelatedPins(ctx context.Context, postID uint, relatedIDs []uint) error
+	CountByIDs(ctx context.Context, ids []uint) (int64, error)
+	Update(ctx context.Context, post *model.BlogPost, events ...model.DomainEvent) error
+	Delete(ctx context.Context, id uint, events ...model.DomainEvent) error
+	GetBaseQuery(ctx context.Context) *gorm.DB
+}
+
+// CommentRepository defines methods for blog comment repository
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id uint) (*model.Comment, error)
+	GetApproved(ctx context.Context, postID uint) ([]model.Comment, error)
+	GetAll(ctx context.Context, filter model.CommentFilter, page, pageSize int) (*paging.Paginator, error)
+	CountSince(ctx context.Context, ipHash string, since time.Time) (int64, error)
+	ExistsDuplicate(ctx context.Context, postID uint, email, content string, since time.Time) (bool, error)
+	HasApproved(ctx context.Context, email string) (bool, error)
+	SetStatus(ctx context.Context, ids []uint, status string) (int64, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// ReactionRepository defines methods for the reaction counters of posts and projects
+type ReactionRepository interface {
+	Add(ctx context.Context, contentType string, contentID uint, emoji, fingerprint string) (bool, error)
+	Summary(ctx context.Context, contentType string, contentID uint) (*model.ReactionSummary, error)
+}
+
+// NewsletterRepository defines methods for newsletter subscriber and issue repository
+type NewsletterRepository interface {
+	CreateSubscriber(ctx context.Context, subscriber *model.Subscriber) error
+	GetSubscriberByID(ctx context.Context, id uint) (*model.Subscriber, error)
+	GetSubscriberByEmail(ctx context.Context, email string) (*model.Subscriber, error)
+	GetSubscriberByConfirmToken(ctx context.Context, token string) (*model.Subscriber, error)
+	GetSubscriberByUnsubscribeToken(ctx context.Context, token string) (*model.Subscriber, error)
+	GetSubscribers(ctx context.Context, status string, page, pageSize int) (*paging.Paginator, error)
+	NextActiveSubscribers(ctx context.Context, afterID uint, limit int) ([]model.Subscriber, error)
+	UpdateSubscriber(ctx context.Context, subscriber *model.Subscriber) error
+	DeleteSubscriber(ctx context.Context, id uint) error
+	GetDigestPosts(ctx context.Context, since, now time.Time) ([]model.BlogPost, error)
+	CreateIssue(ctx context.Context, issue *model.NewsletterIssue) error
+	GetOpenIssue(ctx context.Context) (*model.NewsletterIssue, error)
+	UpdateIssueProgress(ctx context.Context, issue *model.NewsletterIssue) error
+	GetIssues(ctx context.Context, page, pageSize int) (*paging.Paginator, error)
+}
+
+// WebhookRepository defines methods for webhook and delivery queue repository
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	GetByID(ctx context.Context, id uint) (*model.Webhook, error)
+	GetAll(ctx context.Context) ([]model.Webhook, error)
+	GetActive(ctx context.Context) ([]model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	Delete(ctx context.Context, id uint) error
+	CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, webhookID uint, status string, page, pageSize int) (*paging.Paginator, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+// OutboxRepository defines methods for the domain event outbox.
+// Events are written by the repository methods that take them, inside their transaction.
+type OutboxRepository interface {
+	Create(ctx context.Context, events ...model.DomainEvent) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error)
+	Update(ctx context.Context, event *model.OutboxEvent) error
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
+
+// HealthRepository defines the database checks behind the readiness probe
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	PendingMigrations(ctx context.Context) (int, error)
+}
+
+// JobRepository defines methods for the background job queue and its recurring schedules
+type JobRepository interface {
+	Create(ctx context.Context, job *model.Job) error
+	GetByID(ctx context.Context, id uint) (*model.Job, error)
+	GetAll(ctx context.Context, filter model.JobFilter, page, pageSize int) (*paging.Paginator, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	ClaimNext(ctx context.Context, kinds []string, now time.Time, lease time.Duration) (*model.Job, error)
+	Update(ctx context.Context, job *model.Job) error
+	Delete(ctx context.Context, id uint) error
+	DeleteSucceeded(ctx context.Context, before time.Time) (int64, error)
+	SaveSchedule(ctx context.Context, schedule *model.JobSchedule) error
+	GetSchedules(ctx context.Context) ([]model.JobSchedule, error)
+	FireSchedules(ctx context.Context, names []string, now time.Time, fire func(schedule *model.JobSchedule) (*model.Job, time.Time)) (int, error)
 }
 
 // PortfolioRepository defines methods for portfolio project repository
 type PortfolioRepository interface {
-	Create(project *model.PortfolioProject) error
-	GetByID(id uint) (*model.PortfolioProject, error)
-	GetAll(projectType string, page, pageSize int) (*paging.Paginator, error)
-	Update(project *model.PortfolioProject) error
-	Delete(id uint) error
-	GetBaseQuery() *gorm.DB
+	Create(ctx context.Context, project *model.PortfolioProject, events ...model.DomainEvent) error
+	GetByID(ctx context.Context, id uint) (*model.PortfolioProject, error)
+	GetAll(ctx context.Context, filter model.ProjectFilter, page, pageSize int) (*paging.Paginator, error)
+	Update(ctx context.Context, project *model.PortfolioProject, events ...model.DomainEvent) error
+	Reorder(ctx context.Context, ids []uint) error
+	CountByIDs(ctx context.Context, ids []uint) (int64, error)
+	GetLinkedToRepositories(ctx context.Context) ([]model.PortfolioProject, error)
+	UpdateRepositoryStats(ctx context.Context, id uint, stars int, lastCommitAt *time.Time, syncedAt time.Time) error
+	Delete(ctx context.Context, id uint, events ...model.DomainEvent) error
+	GetBaseQuery(ctx context.Context) *gorm.DB
+}
+
+// ProjectTypeRepository defines methods for project type repository
+type ProjectTypeRepository interface {
+	Create(ctx context.Context, projectType *model.ProjectType) error
+	GetByID(ctx context.Context, id uint) (*model.ProjectType, error)
+	GetBySlug(ctx context.Context, slug string) (*model.ProjectType, error)
+	GetAllWithCounts(ctx context.Context) ([]model.ProjectType, error)
+	CountProjects(ctx context.Context, slug string) (int64, error)
+	Update(ctx context.Context, projectType *model.ProjectType) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// TechnologyRepository defines methods for technology catalog repository
+type TechnologyRepository interface {
+	Create(ctx context.Context, technology *model.Technology) error
+	GetByID(ctx context.Context, id uint) (*model.Technology, error)
+	FindByName(ctx context.Context, name string) (*model.Technology, error)
+	GetAllWithCounts(ctx context.Context) ([]model.Technology, error)
+	Update(ctx context.Context, technology *model.Technology) error
+	Delete(ctx context.Context, id uint) error
 }
 
 // ContactRepository defines methods for contact submission repository
 type ContactRepository interface {
-	Create(submission *model.ContactSubmission) error
-	GetAll(page, pageSize int) (*paging.Paginator, error)
-	MarkAsRead(id uint) error
-	Delete(id uint) error
-	GetBaseQuery() *gorm.DB
+	Create(ctx context.Context, submission *model.ContactSubmission, events ...model.DomainEvent) error
+	GetAll(ctx context.Context, page, pageSize int) (*paging.Paginator, error)
+	MarkAsRead(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+	GetBaseQuery(ctx context.Context) *gorm.DB
 }
 
 // UserRepository defines methods for user repository
 type UserRepository interface {
-	Create(user *model.User) error
-	GetByID(id uint) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
-	GetByEmail(email string) (*model.User, error)
-	Update(user *model.User) error
-	Delete(id uint) error
+	Create(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	CountByRole(ctx context.Context, role string) (int64, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// SeriesRepository defines methods for blog post series repository
+type SeriesRepository interface {
+	Create(ctx context.Context, series *model.Series) error
+	GetByID(ctx context.Context, id uint) (*model.Series, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Series, error)
+	GetAllWithCounts(ctx context.Context, now time.Time) ([]model.Series, error)
+	VisibleParts(ctx context.Context, seriesID uint, now time.Time) ([]model.SeriesPart, error)
+	CountPostsByIDs(ctx context.Context, ids []uint) (int64, error)
+	SetPosts(ctx context.Context, seriesID uint, postIDs []uint) error
+	Update(ctx context.Context, series *model.Series) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// AnalyticsRepository defines methods for recording page views and reading their rollups
+type AnalyticsRepository interface {
+	ContentExists(ctx context.Context, contentType, key string, now time.Time) (bool, error)
+	Salt(ctx context.Context, day time.Time, candidate string) (string, error)
+	DeleteSaltsBefore(ctx context.Context, day time.Time) error
+	RecordView(ctx context.Context, view *model.PageView) error
+	Rollup(ctx context.Context, since time.Time) error
+	PurgeViewsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	TopContent(ctx context.Context, q model.AnalyticsQuery) ([]model.ContentStat, error)
+	TimeSeries(ctx context.Context, q model.AnalyticsQuery) ([]model.AnalyticsPoint, error)
+	Referrers(ctx context.Context, q model.AnalyticsQuery) ([]model.ReferrerStat, error)
+}
+
+// SitemapRepository defines methods for listing the publicly visible content
+type SitemapRepository interface {
+	Posts(ctx context.Context, now time.Time) ([]model.SitemapEntry, error)
+	Tags(ctx context.Context, now time.Time) ([]model.SitemapEntry, error)
+	Projects(ctx context.Context) ([]model.SitemapEntry, error)
+}
+
+// ArchiveRepository defines methods for exporting and importing all content
+type ArchiveRepository interface {
+	EachProjectType(ctx context.Context, fn func(projectType *model.ProjectType) error) error
+	EachSeries(ctx context.Context, fn func(series *model.Series) error) error
+	EachPost(ctx context.Context, fn func(post *model.BlogPost) error) error
+	EachProject(ctx context.Context, fn func(project *model.PortfolioProject) error) error
+	EachContact(ctx context.Context, fn func(submission *model.ContactSubmission) error) error
+	EachUser(ctx context.Context, fn func(user *model.User) error) error
+	Import(ctx context.Context, dryRun bool, fn func(importer ArchiveImporter) error) error
+}
+
+// ArchiveImporter upserts archived records inside an import transaction
+type ArchiveImporter interface {
+	UpsertProjectType(projectType *model.ProjectType) (created bool, err error)
+	UpsertSeries(series *model.Series) (created bool, err error)
+	SeriesIDBySlug(slug string) (*uint, error)
+	UpsertPost(post *model.BlogPost) (created bool, err error)
+	UpsertProject(project *model.PortfolioProject) (created bool, err error)
+	UpsertContact(submission *model.ContactSubmission) (created bool, err error)
+	UpsertUser(user *model.User) (created bool, err error)
 }
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+)
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+func (r *seriesRepository) Create(ctx context.Context, series *model.Series) error {
+	return r.db.WithContext(ctx).Create(series).Error
+}
+
+func (r *seriesRepository) GetByID(ctx context.Context, id uint) (*model.Series, error) {
+	var series model.Series
+	err := r.db.WithContext(ctx).First(&series, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *seriesRepository) GetBySlug(ctx context.Context, slug string) (*model.Series, error) {
+	var series model.Series
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&series).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// GetAllWithCounts lists every series with the number of posts visible at now
+func (r *seriesRepository) GetAllWithCounts(ctx context.Context, now time.Time) ([]model.Series, error) {
+	var series []model.Series
+	err := r.db.WithContext(ctx).Model(&model.Series{}).
+		Select("series.*, COUNT(blog_posts.id) AS post_count").
+		Joins("LEFT JOIN blog_posts ON blog_posts.series_id = series.id AND blog_posts.deleted_at IS NULL AND blog_posts.published = ? AND blog_posts.publish_at <= ?", true, now).
+		Group("series.id").
+		Order("series.title ASC").
+		Find(&series).Error
+	return series, err
+}
+
+// VisibleParts lists the published posts of a series whose PublishAt has passed, in series order
+func (r *seriesRepository) VisibleParts(ctx context.Context, seriesID uint, now time.Time) ([]model.SeriesPart, error) {
+	var posts []model.BlogPost
+	err := r.db.WithContext(ctx).Select("id", "title", "slug", "summary", "publish_at").
+		Where("series_id = ? AND published = ? AND publish_at <= ?", seriesID, true, now).
+		Order("series_position ASC, publish_at ASC, id ASC").
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]model.SeriesPart, len(posts))
+	for i, post := range posts {
+		parts[i] = model.SeriesPart{
+			Part:      i + 1,
+			ID:        post.ID,
+			Title:     post.Title,
+			Slug:      post.Slug,
+			Summary:   post.Summary,
+			PublishAt: post.PublishAt,
+		}
+	}
+	return parts, nil
+}
+
+func (r *seriesRepository) CountPostsByIDs(ctx context.Context, ids []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.BlogPost{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+// SetPosts makes postIDs the members of a series in the given order. Posts
+// leave any series they were in before, and former members are released.
+func (r *seriesRepository) SetPosts(ctx context.Context, seriesID uint, postIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		release := tx.Model(&model.BlogPost{}).Where("series_id = ?", seriesID)
+		if len(postIDs) > 0 {
+			release = release.Where("id NOT IN ?", postIDs)
+		}
+		if err := release.Updates(map[string]interface{}{"series_id": nil, "series_position": 0}).Error; err != nil {
+			return err
+		}
+
+		for i, id := range postIDs {
+			err := tx.Model(&model.BlogPost{}).
+				Where("id = ?", id).
+				Updates(map[string]interface{}{"series_id": seriesID, "series_position": i + 1}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *seriesRepository) Update(ctx context.Context, series *model.Series) error {
+	return r.db.WithContext(ctx).Save(series).Error
+}
+
+func (r *seriesRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.BlogPost{}).
+			Where("series_id = ?", id).
+			Updates(map[string]interface{}{"series_id": nil, "series_position": 0}).Error
+		if err != nil {
+			return err
+		}
+
+		// Hard delete so the slug can be reused
+		return tx.Unscoped().Delete(&model.Series{}, id).Error
+	})
+}
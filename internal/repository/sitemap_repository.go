@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+)
+
+type sitemapRepository struct {
+	db *gorm.DB
+}
+
+func NewSitemapRepository(db *gorm.DB) SitemapRepository {
+	return &sitemapRepository{db: db}
+}
+
+type sitemapRow struct {
+	Key     string
+	LastMod time.Time
+}
+
+// Posts lists published posts whose PublishAt has passed, leaving out noindex ones. A scheduled post counts
+// as modified when it goes live, so lastmod never predates PublishAt.
+func (r *sitemapRepository) Posts(ctx context.Context, now time.Time) ([]model.SitemapEntry, error) {
+	var rows []sitemapRow
+	err := r.db.WithContext(ctx).Model(&model.BlogPost{}).
+		Select("slug AS key, GREATEST(updated_at, publish_at) AS last_mod").
+		Where("published = ? AND publish_at <= ? AND noindex = ?", true, now, false).
+		Order("last_mod DESC").
+		Scan(&rows).Error
+	return sitemapEntries(rows), err
+}
+
+// Tags lists tags used by at least one visible post, last modified with their latest post
+func (r *sitemapRepository) Tags(ctx context.Context, now time.Time) ([]model.SitemapEntry, error) {
+	var rows []sitemapRow
+	err := r.db.WithContext(ctx).Table("tags").
+		Select("tags.name AS key, MAX(GREATEST(blog_posts.updated_at, blog_posts.publish_at)) AS last_mod").
+		Joins("JOIN blog_tags ON blog_tags.tag_id = tags.id").
+		Joins("JOIN blog_posts ON blog_posts.id = blog_tags.blog_post_id").
+		Where("tags.deleted_at IS NULL AND blog_posts.deleted_at IS NULL").
+		Where("blog_posts.published = ? AND blog_posts.publish_at <= ? AND blog_posts.noindex = ?", true, now, false).
+		Group("tags.name").
+		Order("tags.name").
+		Scan(&rows).Error
+	return sitemapEntries(rows), err
+}
+
+func (r *sitemapRepository) Projects(ctx context.Context) ([]model.SitemapEntry, error) {
+	var projects []model.PortfolioProject
+	if err := r.db.WithContext(ctx).Select("id", "updated_at").Where("noindex = ?", false).Order("id").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.SitemapEntry, len(projects))
+	for i, project := range projects {
+		entries[i] = model.SitemapEntry{Key: strconv.FormatUint(uint64(project.ID), 10), LastMod: project.UpdatedAt}
+	}
+	return entries, nil
+}
+
+func sitemapEntries(rows []sitemapRow) []model.SitemapEntry {
+	entries := make([]model.SitemapEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = model.SitemapEntry{Key: row.Key, LastMod: row.LastMod}
+	}
+	return entries
+}
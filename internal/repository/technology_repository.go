@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"gorm.io/gorm"
+)
+
+type technologyRepository struct {
+	db *gorm.DB
+}
+
+func NewTechnologyRepository(db *gorm.DB) TechnologyRepository {
+	return &technologyRepository{db: db}
+}
+
+func (r *technologyRepository) Create(ctx context.Context, technology *model.Technology) error {
+	technology.AliasRecords = aliasRecords(technology.Aliases)
+	return r.db.WithContext(ctx).Create(technology).Error
+}
+
+func (r *technologyRepository) GetByID(ctx context.Context, id uint) (*model.Technology, error) {
+	var technology model.Technology
+	err := r.db.WithContext(ctx).Preload("AliasRecords").First(&technology, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	fillAliases(&technology)
+	return &technology, nil
+}
+
+func (r *technologyRepository) FindByName(ctx context.Context, name string) (*model.Technology, error) {
+	return findTechnology(r.db.WithContext(ctx), name)
+}
+
+func (r *technologyRepository) GetAllWithCounts(ctx context.Context) ([]model.Technology, error) {
+	var technologies []model.Technology
+	err := r.db.WithContext(ctx).Model(&model.Technology{}).
+		Preload("AliasRecords").
+		Select("technologies.*, COUNT(portfolio_projects.id) AS project_count").
+		Joins("LEFT JOIN project_technologies ON project_technologies.technology_id = technologies.id").
+		Joins("LEFT JOIN portfolio_projects ON portfolio_projects.id = project_technologies.portfolio_project_id AND portfolio_projects.deleted_at IS NULL").
+		Group("technologies.id").
+		Order("project_count DESC, technologies.name ASC").
+		Find(&technologies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range technologies {
+		fillAliases(&technologies[i])
+	}
+	return technologies, nil
+}
+
+func (r *technologyRepository) Update(ctx context.Context, technology *model.Technology) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("technology_id = ?", technology.ID).Delete(&model.TechnologyAlias{}).Error; err != nil {
+			return err
+		}
+
+		technology.AliasRecords = aliasRecords(technology.Aliases)
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(technology).Error
+	})
+}
+
+func (r *technologyRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM project_technologies WHERE technology_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("technology_id = ?", id).Delete(&model.TechnologyAlias{}).Error; err != nil {
+			return err
+		}
+		// Hard delete so the name can be reused
+		return tx.Unscoped().Delete(&model.Technology{}, id).Error
+	})
+}
+
+// findTechnology looks a technology up by its canonical name or one of its aliases, ignoring case
+func findTechnology(db *gorm.DB, name string) (*model.Technology, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+
+	var technology model.Technology
+	err := db.Where("LOWER(name) = ?", key).
+		Or("id IN (?)", db.Model(&model.TechnologyAlias{}).Select("technology_id").Where("alias = ?", key)).
+		First(&technology).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &technology, nil
+}
+
+// resolveTechnologies maps free-form names onto catalog entries, adding unknown
+// names to the catalog as they are first used
+func resolveTechnologies(db *gorm.DB, names []string) ([]model.Technology, error) {
+	technologies := make([]model.Technology, 0, len(names))
+	seen := make(map[uint]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		technology, err := findTechnology(db, name)
+		if err != nil {
+			return nil, err
+		}
+		if technology == nil {
+			technology = &model.Technology{Name: name}
+			if err := db.Create(technology).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		if !seen[technology.ID] {
+			seen[technology.ID] = true
+			technologies = append(technologies, *technology)
+		}
+	}
+	return technologies, nil
+}
+
+func technologyNames(technologies []model.Technology) []string {
+	names := make([]string, len(technologies))
+	for i, technology := range technologies {
+		names[i] = technology.Name
+	}
+	return names
+}
+
+func aliasRecords(aliases []string) []model.TechnologyAlias {
+	records := make([]model.TechnologyAlias, 0, len(aliases))
+	seen := make(map[string]bool)
+	for _, alias := range aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias != "" && !seen[alias] {
+			seen[alias] = true
+			records = append(records, model.TechnologyAlias{Alias: alias})
+		}
+	}
+	return records
+}
+
+func fillAliases(technology *model.Technology) {
+	technology.Aliases = make([]string, len(technology.AliasRecords))
+	for i, record := range technology.AliasRecords {
+		technology.Aliases[i] = record.Alias
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+const (
+	analyticsDefaultDays  = 30
+	analyticsMaxDays      = 366
+	analyticsDefaultLimit = 10
+	analyticsMaxLimit     = 100
+	analyticsMaxFieldLen  = 100
+)
+
+// botUserAgentRe matches crawlers, link preview fetchers and scripted clients
+var botUserAgentRe = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|scrape|fetch|preview|headless|lighthouse|pingdom|monitor|curl|wget|python-|go-http-client|java/|okhttp|axios|node-fetch|httpclient|facebookexternalhit|embedly|whatsapp|telegram|discord`)
+
+type analyticsService struct {
+	repo     repository.AnalyticsRepository
+	cfg      config.AnalyticsConfig
+	siteHost string
+
+	mu      sync.Mutex
+	saltDay time.Time
+	salt    string
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepository, cfg config.AnalyticsConfig, site config.SiteConfig) AnalyticsService {
+	s := &analyticsService{repo: repo, cfg: cfg}
+	if siteURL, err := url.Parse(site.URL); err == nil {
+		s.siteHost = normalizeHost(siteURL.Hostname())
+	}
+	return s
+}
+
+// RecordView stores one view or read. Bots are dropped silently; the visitor is
+// only kept as a hash of IP and user agent with a salt that changes every day.
+func (s *analyticsService) RecordView(ctx context.Context, req model.PageViewRequest, ip, userAgent string) (err error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsService.RecordView")
+	defer endSpan(span, &err)
+
+	if !s.cfg.Enabled || isBotUserAgent(userAgent) {
+		return nil
+	}
+
+	view := model.PageView{
+		ContentType: strings.ToLower(strings.TrimSpace(req.Type)),
+		ContentKey:  strings.TrimSpace(req.Key),
+		Event:       strings.ToLower(strings.TrimSpace(req.Event)),
+		UTMSource:   cleanAnalyticsField(req.UTMSource),
+		UTMMedium:   cleanAnalyticsField(req.UTMMedium),
+		UTMCampaign: cleanAnalyticsField(req.UTMCampaign),
+	}
+	if view.Event == "" {
+		view.Event = model.AnalyticsEventView
+	}
+	if view.Event != model.AnalyticsEventView && view.Event != model.AnalyticsEventRead {
+		return newValidationError("event must be view or read")
+	}
+	if view.ContentType != model.AnalyticsContentPost && view.ContentType != model.AnalyticsContentProject {
+		return newValidationError("type must be post or project")
+	}
+	if view.ContentKey == "" || len(view.ContentKey) > analyticsMaxFieldLen {
+		return newValidationError("invalid key")
+	}
+	if view.ContentType == model.AnalyticsContentProject {
+		// Projects are keyed by ID; the canonical form keeps "07" and "7" together
+		id, err := strconv.ParseUint(view.ContentKey, 10, 32)
+		if err != nil {
+			return newValidationError("key of a project must be its ID")
+		}
+		view.ContentKey = strconv.FormatUint(id, 10)
+	}
+
+	now := time.Now().UTC()
+	exists, err := s.repo.ContentExists(ctx, view.ContentType, view.ContentKey, now)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newValidationError(fmt.Sprintf("unknown %s %q", view.ContentType, view.ContentKey))
+	}
+
+	salt, err := s.dailySalt(ctx, now)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(salt + "|" + ip + "|" + userAgent))
+	view.VisitorHash = hex.EncodeToString(sum[:16])
+	view.ReferrerHost = s.referrerHost(req.Referrer)
+
+	return s.repo.RecordView(ctx, &view)
+}
+
+func (s *analyticsService) TopContent(ctx context.Context, q model.AnalyticsQuery) (_ []model.ContentStat, err error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsService.TopContent")
+	defer endSpan(span, &err)
+
+	if err := normalizeAnalyticsQuery(&q); err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.TopContent(ctx, q)
+	if stats == nil && err == nil {
+		stats = []model.ContentStat{}
+	}
+	return stats, err
+}
+
+// TimeSeries returns one point per day of the range, including days without traffic
+func (s *analyticsService) TimeSeries(ctx context.Context, q model.AnalyticsQuery) (_ []model.AnalyticsPoint, err error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsService.TimeSeries")
+	defer endSpan(span, &err)
+
+	if err := normalizeAnalyticsQuery(&q); err != nil {
+		return nil, err
+	}
+	points, err := s.repo.TimeSeries(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[string]model.AnalyticsPoint, len(points))
+	for _, point := range points {
+		byDay[point.Day] = point
+	}
+
+	series := []model.AnalyticsPoint{}
+	for day := q.From; !day.After(q.To); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		point, ok := byDay[key]
+		if !ok {
+			point = model.AnalyticsPoint{Day: key}
+		}
+		series = append(series, point)
+	}
+	return series, nil
+}
+
+func (s *analyticsService) Referrers(ctx context.Context, q model.AnalyticsQuery) (_ []model.ReferrerStat, err error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Referrers")
+	defer endSpan(span, &err)
+
+	if err := normalizeAnalyticsQuery(&q); err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.Referrers(ctx, q)
+	if stats == nil && err == nil {
+		stats = []model.ReferrerStat{}
+	}
+	return stats, err
+}
+
+// Rollup refreshes the daily tables, then drops raw events and salts that are no longer needed
+func (s *analyticsService) Rollup(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsService.Rollup")
+	defer endSpan(span, &err)
+
+	now := time.Now().UTC()
+
+	// A day is purged only after a rollup has run past its end
+	retention := s.cfg.RawRetention
+	if minimum := 2 * s.cfg.RollupInterval; retention < minimum {
+		retention = minimum
+	}
+	cutoff := utcDay(now.Add(-retention))
+
+	if err := s.repo.Rollup(ctx, cutoff); err != nil {
+		return err
+	}
+	if _, err := s.repo.PurgeViewsBefore(ctx, cutoff); err != nil {
+		return err
+	}
+	return s.repo.DeleteSaltsBefore(ctx, utcDay(now))
+}
+
+func (s *analyticsService) dailySalt(ctx context.Context, now time.Time) (string, error) {
+	day := utcDay(now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.salt != "" && s.saltDay.Equal(day) {
+		return s.salt, nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	salt, err := s.repo.Salt(ctx, day, hex.EncodeToString(random))
+	if err != nil {
+		return "", err
+	}
+	s.saltDay, s.salt = day, salt
+	return salt, nil
+}
+
+// referrerHost keeps only the host of an external referrer
+func (s *analyticsService) referrerHost(referrer string) string {
+	parsed, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := normalizeHost(parsed.Hostname())
+	if host == s.siteHost {
+		return ""
+	}
+	return cleanAnalyticsField(host)
+}
+
+func normalizeAnalyticsQuery(q *model.AnalyticsQuery) error {
+	q.ContentType = strings.ToLower(strings.TrimSpace(q.ContentType))
+	if q.ContentType != "" && q.ContentType != model.AnalyticsContentPost && q.ContentType != model.AnalyticsContentProject {
+		return newValidationError("type must be post or project")
+	}
+	if q.ContentKey != "" && q.ContentType == "" {
+		return newValidationError("key requires type")
+	}
+
+	q.Event = strings.ToLower(strings.TrimSpace(q.Event))
+	if q.Event == "" {
+		q.Event = model.AnalyticsEventView
+	}
+	if q.Event != model.AnalyticsEventView && q.Event != model.AnalyticsEventRead {
+		return newValidationError("event must be view or read")
+	}
+
+	if q.To.IsZero() {
+		q.To = time.Now().UTC()
+	}
+	q.To = utcDay(q.To)
+	if q.From.IsZero() {
+		q.From = q.To.AddDate(0, 0, -(analyticsDefaultDays - 1))
+	}
+	q.From = utcDay(q.From)
+	if q.From.After(q.To) {
+		return newValidationError("from must not be after to")
+	}
+	if q.To.Sub(q.From) >= analyticsMaxDays*24*time.Hour {
+		return newValidationError(fmt.Sprintf("ranges are limited to %d days", analyticsMaxDays))
+	}
+
+	if q.Limit < 1 {
+		q.Limit = analyticsDefaultLimit
+	}
+	if q.Limit > analyticsMaxLimit {
+		q.Limit = analyticsMaxLimit
+	}
+	return nil
+}
+
+func isBotUserAgent(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botUserAgentRe.MatchString(userAgent)
+}
+
+func cleanAnalyticsField(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if runes := []rune(value); len(runes) > analyticsMaxFieldLen {
+		value = string(runes[:analyticsMaxFieldLen])
+	}
+	return value
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
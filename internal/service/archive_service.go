@@ -0,0 +1,479 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+const (
+	archiveVersion = 1
+	// maxMediaSize caps a single media download so one huge file cannot stall an export
+	maxMediaSize = 25 << 20
+)
+
+// Archive entities, in the order they are exported and imported
+const (
+	archiveProjectTypes = "project_types"
+	archiveSeries       = "series"
+	archivePosts        = "posts"
+	archiveProjects     = "projects"
+	archiveContacts     = "contacts"
+	archiveUsers        = "users"
+)
+
+type archiveService struct {
+	repo       repository.ArchiveRepository
+	httpClient *http.Client
+}
+
+func NewArchiveService(repo repository.ArchiveRepository) ArchiveService {
+	return &archiveService{
+		repo:       repo,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Export writes a zip archive of all content to w
+func (s *archiveService) Export(ctx context.Context, w io.Writer, opts model.ExportOptions) (err error) {
+	ctx, span := tracer.Start(ctx, "ArchiveService.Export")
+	defer endSpan(span, &err)
+
+	if opts.Format == "" {
+		opts.Format = model.ArchiveFormatJSON
+	}
+	if opts.Format != model.ArchiveFormatJSON && opts.Format != model.ArchiveFormatNDJSON {
+		return newValidationError(fmt.Sprintf("unknown archive format %q", opts.Format))
+	}
+
+	zw := zip.NewWriter(w)
+	manifest := model.ArchiveManifest{
+		Version:                archiveVersion,
+		ExportedAt:             time.Now().UTC(),
+		Format:                 opts.Format,
+		IncludesPasswordHashes: opts.IncludePasswordHashes,
+		IncludesMedia:          opts.IncludeMedia,
+		Counts:                 make(map[string]int),
+	}
+	var mediaURLs []string
+	seriesSlugs := make(map[uint]string)
+
+	sections := []struct {
+		name   string
+		export func(write func(record interface{}) error) error
+	}{
+		{archiveProjectTypes, func(write func(interface{}) error) error {
+			return s.repo.EachProjectType(ctx, func(projectType *model.ProjectType) error {
+				return write(projectType)
+			})
+		}},
+		{archiveSeries, func(write func(interface{}) error) error {
+			return s.repo.EachSeries(ctx, func(series *model.Series) error {
+				seriesSlugs[series.ID] = series.Slug
+				return write(series)
+			})
+		}},
+		{archivePosts, func(write func(interface{}) error) error {
+			return s.repo.EachPost(ctx, func(post *model.BlogPost) error {
+				mediaURLs = append(mediaURLs, post.ImageURL)
+				record := model.ArchivePost{BlogPost: *post}
+				if post.SeriesID != nil {
+					record.SeriesSlug = seriesSlugs[*post.SeriesID]
+				}
+				return write(record)
+			})
+		}},
+		{archiveProjects, func(write func(interface{}) error) error {
+			return s.repo.EachProject(ctx, func(project *model.PortfolioProject) error {
+				mediaURLs = append(mediaURLs, project.ImageURL)
+				if project.Music != nil {
+					for _, track := range project.Music.Tracks {
+						mediaURLs = append(mediaURLs, track.AudioURL)
+					}
+				}
+				return write(project)
+			})
+		}},
+		{archiveContacts, func(write func(interface{}) error) error {
+			return s.repo.EachContact(ctx, func(submission *model.ContactSubmission) error {
+				return write(submission)
+			})
+		}},
+		{archiveUsers, func(write func(interface{}) error) error {
+			return s.repo.EachUser(ctx, func(user *model.User) error {
+				record := model.ArchiveUser{User: *user}
+				if opts.IncludePasswordHashes {
+					record.PasswordHash = user.PasswordHash
+				}
+				return write(record)
+			})
+		}},
+	}
+
+	for _, section := range sections {
+		file, err := zw.Create(section.name + "." + opts.Format)
+		if err != nil {
+			return err
+		}
+
+		records := newRecordWriter(file, opts.Format)
+		if err := section.export(records.write); err != nil {
+			return fmt.Errorf("exporting %s: %w", section.name, err)
+		}
+		if err := records.close(); err != nil {
+			return err
+		}
+		manifest.Counts[section.name] = records.count
+	}
+
+	if opts.IncludeMedia {
+		if err := s.exportMedia(ctx, zw, mediaURLs); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSONFile(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// exportMedia downloads every referenced http(s) file into media/ and lists
+// them in media.json; failed downloads are recorded there instead of failing the export
+func (s *archiveService) exportMedia(ctx context.Context, zw *zip.Writer, urls []string) error {
+	type mediaEntry struct {
+		URL   string `json:"url"`
+		Path  string `json:"path,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	var entries []mediaEntry
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if seen[url] || !(strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+			continue
+		}
+		seen[url] = true
+
+		sum := sha256.Sum256([]byte(url))
+		name := "media/" + hex.EncodeToString(sum[:8]) + "-" + path.Base(strings.SplitN(url, "?", 2)[0])
+		if err := s.downloadMedia(ctx, zw, url, name); err != nil {
+			entries = append(entries, mediaEntry{URL: url, Error: err.Error()})
+			continue
+		}
+		entries = append(entries, mediaEntry{URL: url, Path: name})
+	}
+
+	return writeJSONFile(zw, "media.json", entries)
+}
+
+func (s *archiveService) downloadMedia(ctx context.Context, zw *zip.Writer, url, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	// Buffer first so a failed or oversized download leaves no partial entry behind
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxMediaSize {
+		return fmt.Errorf("larger than %d bytes", maxMediaSize)
+	}
+
+	file, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(body)
+	return err
+}
+
+// Import upserts the content of an archive: posts, series and project types by
+// slug, projects by title and type, contacts by sender and time and users by
+// username. Media files are counted as skipped rather than restored. With
+// dryRun everything is rolled back and only the report is kept.
+func (s *archiveService) Import(ctx context.Context, r io.ReaderAt, size int64, dryRun bool) (_ *model.ImportReport, err error) {
+	ctx, span := tracer.Start(ctx, "ArchiveService.Import")
+	defer endSpan(span, &err)
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("invalid archive: %v", err))
+	}
+
+	var manifest model.ArchiveManifest
+	if err := readJSONFile(zr, "manifest.json", &manifest); err != nil {
+		return nil, newValidationError(fmt.Sprintf("invalid archive manifest: %v", err))
+	}
+	if manifest.Version > archiveVersion {
+		return nil, newValidationError(fmt.Sprintf("archive version %d is newer than supported version %d", manifest.Version, archiveVersion))
+	}
+
+	report := &model.ImportReport{
+		DryRun:   dryRun,
+		Entities: make(map[string]*model.ImportEntityReport),
+		Errors:   []model.ImportError{},
+	}
+
+	err = s.repo.Import(ctx, dryRun, func(importer repository.ArchiveImporter) error {
+		steps := []struct {
+			name   string
+			upsert func(raw json.RawMessage) (key string, created bool, err error)
+		}{
+			{archiveProjectTypes, func(raw json.RawMessage) (string, bool, error) {
+				var projectType model.ProjectType
+				if err := json.Unmarshal(raw, &projectType); err != nil {
+					return "", false, err
+				}
+				projectType.Slug = normalizeSlug(projectType.Slug)
+				if projectType.Slug == "" || projectType.Name == "" {
+					return projectType.Slug, false, fmt.Errorf("name and slug are required")
+				}
+				created, err := importer.UpsertProjectType(&projectType)
+				return projectType.Slug, created, err
+			}},
+			{archiveSeries, func(raw json.RawMessage) (string, bool, error) {
+				var series model.Series
+				if err := json.Unmarshal(raw, &series); err != nil {
+					return "", false, err
+				}
+				series.Slug = normalizeSlug(series.Slug)
+				if series.Slug == "" || series.Title == "" {
+					return series.Slug, false, fmt.Errorf("title and slug are required")
+				}
+				created, err := importer.UpsertSeries(&series)
+				return series.Slug, created, err
+			}},
+			{archivePosts, func(raw json.RawMessage) (string, bool, error) {
+				var record model.ArchivePost
+				if err := json.Unmarshal(raw, &record); err != nil {
+					return "", false, err
+				}
+				post := record.BlogPost
+				if post.Slug == "" {
+					return post.Title, false, fmt.Errorf("slug is required")
+				}
+
+				// IDs differ between installations, so membership follows the series slug
+				post.SeriesID, post.SeriesNav = nil, nil
+				if record.SeriesSlug != "" {
+					seriesID, err := importer.SeriesIDBySlug(record.SeriesSlug)
+					if err != nil {
+						return post.Slug, false, err
+					}
+					post.SeriesID = seriesID
+				}
+				created, err := importer.UpsertPost(&post)
+				return post.Slug, created, err
+			}},
+			{archiveProjects, func(raw json.RawMessage) (string, bool, error) {
+				var project model.PortfolioProject
+				if err := json.Unmarshal(raw, &project); err != nil {
+					return "", false, err
+				}
+				project.ProjectType = normalizeSlug(project.ProjectType)
+				if project.Title == "" || project.ProjectType == "" {
+					return project.Title, false, fmt.Errorf("title and project type are required")
+				}
+				if project.Music != nil {
+					if err := validateMusic(project.Music); err != nil {
+						return project.Title, false, err
+					}
+				}
+				created, err := importer.UpsertProject(&project)
+				return project.Title, created, err
+			}},
+			{archiveContacts, func(raw json.RawMessage) (string, bool, error) {
+				var submission model.ContactSubmission
+				if err := json.Unmarshal(raw, &submission); err != nil {
+					return "", false, err
+				}
+				key := submission.Email + " " + submission.CreatedAt.Format(time.RFC3339)
+				created, err := importer.UpsertContact(&submission)
+				return key, created, err
+			}},
+			{archiveUsers, func(raw json.RawMessage) (string, bool, error) {
+				var record model.ArchiveUser
+				if err := json.Unmarshal(raw, &record); err != nil {
+					return "", false, err
+				}
+				if record.Username == "" || record.Email == "" {
+					return record.Username, false, fmt.Errorf("username and email are required")
+				}
+				user := record.User
+				user.PasswordHash = record.PasswordHash
+				created, err := importer.UpsertUser(&user)
+				return user.Username, created, err
+			}},
+		}
+
+		for _, step := range steps {
+			entity := &model.ImportEntityReport{}
+			report.Entities[step.name] = entity
+
+			err := readRecords(zr, step.name+"."+manifest.Format, func(raw json.RawMessage) {
+				key, created, err := step.upsert(raw)
+				switch {
+				case err != nil:
+					entity.Failed++
+					report.Errors = append(report.Errors, model.ImportError{Entity: step.name, Key: key, Error: err.Error()})
+				case created:
+					entity.Created++
+				default:
+					entity.Updated++
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", step.name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Content keeps pointing at the original media URLs, so the copies are only reported
+	for _, file := range zr.File {
+		if strings.HasPrefix(file.Name, "media/") && !strings.HasSuffix(file.Name, "/") {
+			report.SkippedMedia++
+		}
+	}
+
+	return report, nil
+}
+
+// recordWriter writes records either as one JSON array or as NDJSON lines
+type recordWriter struct {
+	w      io.Writer
+	format string
+	count  int
+}
+
+func newRecordWriter(w io.Writer, format string) *recordWriter {
+	return &recordWriter{w: w, format: format}
+}
+
+func (rw *recordWriter) write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if rw.format == model.ArchiveFormatJSON {
+		prefix = ",\n"
+		if rw.count == 0 {
+			prefix = "[\n"
+		}
+	}
+	rw.count++
+
+	if _, err := io.WriteString(rw.w, prefix); err != nil {
+		return err
+	}
+	if _, err := rw.w.Write(data); err != nil {
+		return err
+	}
+	if rw.format == model.ArchiveFormatNDJSON {
+		_, err = io.WriteString(rw.w, "\n")
+	}
+	return err
+}
+
+func (rw *recordWriter) close() error {
+	if rw.format != model.ArchiveFormatJSON {
+		return nil
+	}
+	if rw.count == 0 {
+		_, err := io.WriteString(rw.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(rw.w, "\n]\n")
+	return err
+}
+
+// readRecords calls fn for every record of a JSON array or NDJSON file; a
+// missing file simply has no records
+func readRecords(zr *zip.Reader, name string, fn func(raw json.RawMessage)) error {
+	file, err := zr.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	dec := json.NewDecoder(reader)
+	if first, err := peekNonSpace(reader); err == nil && first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		fn(raw)
+	}
+	return nil
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := reader.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func writeJSONFile(zw *zip.Writer, name string, v interface{}) error {
+	file, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func readJSONFile(zr *zip.Reader, name string, v interface{}) error {
+	file, err := zr.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(v)
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/metrics"
 	"github.com/lutestringamend/perwebbe/internal/middleware"
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/internal/repository"
@@ -25,8 +27,11 @@ func NewAuthService(userRepo repository.UserRepository, jwtConfig config.JWTConf
 	}
 }
 
-func (s *authService) Register(username, email, password string) (*model.User, error) {
-	existingUser, err := s.userRepo.GetByUsername(username)
+func (s *authService) Register(ctx context.Context, username, email, password string) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Register")
+	defer endSpan(span, &err)
+
+	existingUser, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +39,7 @@ func (s *authService) Register(username, email, password string) (*model.User, e
 		return nil, errors.New("username already exists")
 	}
 
-	existingEmail, err := s.userRepo.GetByEmail(email)
+	existingEmail, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
@@ -51,32 +56,38 @@ func (s *authService) Register(username, email, password string) (*model.User, e
 		Username:     username,
 		Email:        email,
 		PasswordHash: string(hashedPassword),
-		Role:         "user",
+		Role:         model.RoleUser,
 		Active:       true,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *authService) Login(email, password string) (*model.AuthResponse, error) {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *authService) Login(ctx context.Context, email, password string) (_ *model.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer endSpan(span, &err)
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
+		metrics.LoginsFailed.Inc()
 		return nil, errors.New("email is not registered")
 	}
 
 	if !user.Active {
+		metrics.LoginsFailed.Inc()
 		return nil, errors.New("account is inactive")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
+		metrics.LoginsFailed.Inc()
 		return nil, errors.New("invalid email or password")
 	}
 
@@ -102,7 +113,10 @@ func (s *authService) Login(email, password string) (*model.AuthResponse, error)
 	return authReponse, nil
 }
 
-func (s *authService) RefreshToken(refreshToken string) (*model.AuthResponse, error) {
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (_ *model.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RefreshToken")
+	defer endSpan(span, &err)
+
 	claims := jwt.MapClaims{}
 	token, err := jwt.ParseWithClaims(refreshToken, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -130,7 +144,7 @@ func (s *authService) RefreshToken(refreshToken string) (*model.AuthResponse, er
 		return nil, err
 	}
 
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,12 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/event"
+	"github.com/lutestringamend/perwebbe/internal/job"
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/internal/repository"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
@@ -8,37 +14,226 @@ import (
 )
 
 type blogService struct {
-	repo repository.BlogRepository
+	repo       repository.BlogRepository
+	seriesRepo repository.SeriesRepository
+	bus        EventBus
+	jobs       JobQueue
+	related    *relatedCache
+}
+
+func NewBlogService(repo repository.BlogRepository, seriesRepo repository.SeriesRepository, bus EventBus, jobs JobQueue) BlogService {
+	return &blogService{repo: repo, seriesRepo: seriesRepo, bus: bus, jobs: jobs, related: newRelatedCache()}
+}
+
+func (s *blogService) CreateBlog(ctx context.Context, post *model.BlogPost) (err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.CreateBlog")
+	defer endSpan(span, &err)
+
+	now := time.Now()
+	events := []model.DomainEvent{event.BlogCreated{Post: post}}
+	if isVisible(post, now) {
+		events = append(events, event.BlogPublished{Post: post})
+	}
+
+	if err := s.repo.Create(ctx, post, events...); err != nil {
+		return err
+	}
+	s.related.clear()
+	s.bus.Wake()
+
+	if isScheduled(post, now) {
+		return s.schedulePublish(ctx, post)
+	}
+	return nil
+}
+
+func (s *blogService) GetBlogByID(ctx context.Context, id uint) (_ *model.BlogPost, err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.GetBlogByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *blogService) GetAllBlogs(ctx context.Context, page, pageSize int, sort string) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.GetAllBlogs")
+	defer endSpan(span, &err)
+
+	switch sort {
+	case "", model.BlogSortCreatedAt, model.BlogSortReactions:
+	default:
+		return nil, newValidationError(fmt.Sprintf("unknown sort option %q", sort))
+	}
+	return s.repo.GetAll(ctx, page, pageSize, sort)
+}
+
+func (s *blogService) GetPublishedBlogs(ctx context.Context, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.GetPublishedBlogs")
+	defer endSpan(span, &err)
+
+	return s.repo.GetPublished(ctx, page, pageSize, time.Now())
+}
+
+// GetBlogBySlug also fills the related posts, and SeriesNav when the post is part of a series
+func (s *blogService) GetBlogBySlug(ctx context.Context, slug string) (_ *model.BlogPost, err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.GetBlogBySlug")
+	defer endSpan(span, &err)
+
+	post, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil || post == nil {
+		return post, err
+	}
+
+	if post.SeriesID != nil {
+		if post.SeriesNav, err = s.seriesNav(ctx, post); err != nil {
+			return nil, err
+		}
+	}
+
+	if post.Related, err = s.relatedPosts(ctx, post); err != nil {
+		return nil, err
+	}
+	return post, nil
 }
 
-func NewBlogService(repo repository.BlogRepository) BlogService {
-	return &blogService{repo: repo}
+// seriesNav links a post to its visible neighbours in the series
+func (s *blogService) seriesNav(ctx context.Context, post *model.BlogPost) (*model.SeriesNav, error) {
+	series, err := s.seriesRepo.GetByID(ctx, *post.SeriesID)
+	if err != nil || series == nil {
+		return nil, err
+	}
+
+	parts, err := s.seriesRepo.VisibleParts(ctx, series.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	nav := &model.SeriesNav{ID: series.ID, Title: series.Title, Slug: series.Slug, Total: len(parts)}
+	for i := range parts {
+		if parts[i].ID != post.ID {
+			continue
+		}
+		nav.Part = parts[i].Part
+		if i > 0 {
+			nav.Previous = &parts[i-1]
+		}
+		if i+1 < len(parts) {
+			nav.Next = &parts[i+1]
+		}
+	}
+	return nav, nil
+}
+
+func (s *blogService) UpdateBlog(ctx context.Context, post *model.BlogPost) (err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.UpdateBlog")
+	defer endSpan(span, &err)
+
+	previous, err := s.repo.GetByID(ctx, post.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	events := []model.DomainEvent{event.BlogUpdated{Post: post}}
+	if isVisible(post, now) && (previous == nil || !isVisible(previous, now)) {
+		events = append(events, event.BlogPublished{Post: post})
+	}
+
+	if err := s.repo.Update(ctx, post, events...); err != nil {
+		return err
+	}
+	if previous == nil || relatedInputsChanged(previous, post) {
+		s.related.clear()
+	}
+	s.bus.Wake()
+
+	// A job queued for the previous schedule finds the post moved and does nothing
+	rescheduled := previous == nil || !isScheduled(previous, now) || !previous.PublishAt.Equal(post.PublishAt)
+	if isScheduled(post, now) && rescheduled {
+		return s.schedulePublish(ctx, post)
+	}
+	return nil
 }
 
-func (s *blogService) CreateBlog(post *model.BlogPost) error {
-	return s.repo.Create(post)
+// PublishScheduled announces a scheduled post once it has gone live. Posts that
+// were unpublished or moved to another time since the job was queued are left alone.
+func (s *blogService) PublishScheduled(ctx context.Context, payload model.BlogPublishPayload) (err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.PublishScheduled")
+	defer endSpan(span, &err)
+
+	post, err := s.repo.GetByID(ctx, payload.PostID)
+	if err != nil {
+		return err
+	}
+	if post == nil || !post.Published || !post.PublishAt.Equal(payload.PublishAt) {
+		return nil
+	}
+	if !isVisible(post, time.Now()) {
+		return fmt.Errorf("post %d goes live at %s", post.ID, post.PublishAt.Format(time.RFC3339))
+	}
+
+	s.related.clear()
+	return s.bus.Publish(ctx, event.BlogPublished{Post: post})
 }
 
-func (s *blogService) GetBlogByID(id uint) (*model.BlogPost, error) {
-	return s.repo.GetByID(id)
+// schedulePublish queues the BlogPublished event of a post for its PublishAt
+func (s *blogService) schedulePublish(ctx context.Context, post *model.BlogPost) error {
+	payload := model.BlogPublishPayload{PostID: post.ID, PublishAt: post.PublishAt}
+	_, err := s.jobs.Enqueue(ctx, model.JobKindBlogPublish, payload, job.RunAt(post.PublishAt))
+	return err
 }
 
-func (s *blogService) GetAllBlogs(page, pageSize int) (*paging.Paginator, error) {
-	return s.repo.GetAll(page, pageSize)
+// isVisible reports whether readers can see the post at now
+func isVisible(post *model.BlogPost, now time.Time) bool {
+	return post.Published && !post.PublishAt.After(now)
 }
 
-func (s *blogService) GetBlogBySlug(slug string) (*model.BlogPost, error) {
-	return s.repo.GetBySlug(slug)
+// isScheduled reports whether the post is published but waits for its PublishAt
+func isScheduled(post *model.BlogPost, now time.Time) bool {
+	return post.Published && post.PublishAt.After(now)
 }
 
-func (s *blogService) UpdateBlog(post *model.BlogPost) error {
-	return s.repo.Update(post)
+func (s *blogService) DeleteBlog(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.DeleteBlog")
+	defer endSpan(span, &err)
+
+	post, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var events []model.DomainEvent
+	if post != nil {
+		events = append(events, event.BlogDeleted{Post: post})
+	}
+
+	if err := s.repo.Delete(ctx, id, events...); err != nil {
+		return err
+	}
+	s.related.clear()
+	s.bus.Wake()
+	return nil
 }
 
-func (s *blogService) DeleteBlog(id uint) error {
-	return s.repo.Delete(id)
+// relatedInputsChanged reports whether an update touches anything the related posts depend on
+func relatedInputsChanged(previous, post *model.BlogPost) bool {
+	if previous.Title != post.Title || previous.Summary != post.Summary || previous.Content != post.Content ||
+		previous.Slug != post.Slug || previous.ImageURL != post.ImageURL ||
+		previous.Published != post.Published || !previous.PublishAt.Equal(post.PublishAt) {
+		return true
+	}
+
+	before, after := tagSet(previous.Tags), tagSet(post.Tags)
+	if len(before) != len(after) {
+		return true
+	}
+	for name := range before {
+		if !after[name] {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *blogService) GetBlogBaseQuery() *gorm.DB {
-	return s.repo.GetBaseQuery()
+func (s *blogService) GetBlogBaseQuery(ctx context.Context) *gorm.DB {
+	return s.repo.GetBaseQuery(ctx)
 }
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+)
+
+const (
+	commentMaxLength       = 5000
+	commentMaxNameLength   = 100
+	commentMaxUserAgentLen = 255
+	commentDuplicateWindow = time.Hour * 24
+)
+
+// commentLinkRe matches anything a reader could follow as a link
+var commentLinkRe = regexp.MustCompile(`(?i)https?://|www\.|\[url`)
+
+type commentService struct {
+	repo     repository.CommentRepository
+	blogRepo repository.BlogRepository
+	userRepo repository.UserRepository
+	cfg      config.CommentConfig
+}
+
+func NewCommentService(repo repository.CommentRepository, blogRepo repository.BlogRepository, userRepo repository.UserRepository, cfg config.CommentConfig) CommentService {
+	return &commentService{repo: repo, blogRepo: blogRepo, userRepo: userRepo, cfg: cfg}
+}
+
+// GetThread returns the approved comments of a visible post as a tree, or nil
+// when there is no such post
+func (s *commentService) GetThread(ctx context.Context, slug string) (_ *model.CommentThread, err error) {
+	ctx, span := tracer.Start(ctx, "CommentService.GetThread")
+	defer endSpan(span, &err)
+
+	post, err := s.visiblePost(ctx, slug)
+	if err != nil || post == nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetApproved(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &model.CommentThread{PostID: post.ID, Closed: post.CommentsClosed}
+	thread.Comments, thread.Count = buildCommentTree(comments)
+	return thread, nil
+}
+
+// Submit stores a comment on a visible post. Logged in authors are published
+// right away; guest comments pass the spam checks and wait for moderation unless
+// their author has been approved before. Returns nil when there is no such post.
+func (s *commentService) Submit(ctx context.Context, slug string, req model.CommentRequest, userID *uint, ip, userAgent string) (_ *model.Comment, err error) {
+	ctx, span := tracer.Start(ctx, "CommentService.Submit")
+	defer endSpan(span, &err)
+
+	post, err := s.visiblePost(ctx, slug)
+	if err != nil || post == nil {
+		return nil, err
+	}
+	if post.CommentsClosed {
+		return nil, newValidationError("comments are closed for this post")
+	}
+
+	comment := &model.Comment{
+		PostID:    post.ID,
+		ParentID:  req.ParentID,
+		Content:   strings.TrimSpace(req.Content),
+		AuthorURL: strings.TrimSpace(req.AuthorURL),
+		IPHash:    hashCommentIP(s.cfg.HashSecret, ip),
+		UserAgent: userAgent,
+	}
+	if runes := []rune(comment.UserAgent); len(runes) > commentMaxUserAgentLen {
+		comment.UserAgent = string(runes[:commentMaxUserAgentLen])
+	}
+	if n := utf8.RuneCountInString(comment.Content); n == 0 || n > commentMaxLength {
+		return nil, newValidationError(fmt.Sprintf("content must be between 1 and %d characters", commentMaxLength))
+	}
+	if comment.AuthorURL != "" {
+		if u, err := url.ParseRequestURI(comment.AuthorURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, newValidationError("author_url must be an http or https URL")
+		}
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.PostID != post.ID || parent.Status != model.CommentStatusApproved {
+			return nil, newValidationError("parent comment not found")
+		}
+	}
+
+	if err := s.setAuthor(ctx, comment, req, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if s.cfg.RateLimit > 0 {
+		count, err := s.repo.CountSince(ctx, comment.IPHash, now.Add(-s.cfg.RateWindow))
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(s.cfg.RateLimit) {
+			return nil, newValidationError("too many comments, please try again later")
+		}
+	}
+
+	duplicate, err := s.repo.ExistsDuplicate(ctx, post.ID, comment.AuthorEmail, comment.Content, now.Add(-commentDuplicateWindow))
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		return nil, newValidationError("duplicate comment")
+	}
+
+	comment.Status = model.CommentStatusPending
+	if comment.UserID != nil {
+		comment.Status = model.CommentStatusApproved
+	} else if comment.SpamReason = s.spamReason(comment, req.Website); comment.SpamReason != "" {
+		comment.Status = model.CommentStatusSpam
+	} else if s.cfg.AutoApproveReturning {
+		returning, err := s.repo.HasApproved(ctx, comment.AuthorEmail)
+		if err != nil {
+			return nil, err
+		}
+		if returning {
+			comment.Status = model.CommentStatusApproved
+		}
+	}
+
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) GetComments(ctx context.Context, filter model.CommentFilter, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "CommentService.GetComments")
+	defer endSpan(span, &err)
+
+	if filter.Status != "" && !isCommentStatus(filter.Status) {
+		return nil, newValidationError("status must be pending, approved, rejected or spam")
+	}
+	return s.repo.GetAll(ctx, filter, page, pageSize)
+}
+
+func (s *commentService) ModerateComments(ctx context.Context, ids []uint, status string) (_ int64, err error) {
+	ctx, span := tracer.Start(ctx, "CommentService.ModerateComments")
+	defer endSpan(span, &err)
+
+	if len(ids) == 0 {
+		return 0, newValidationError("ids must not be empty")
+	}
+	if !isCommentStatus(status) {
+		return 0, newValidationError("status must be pending, approved, rejected or spam")
+	}
+	return s.repo.SetStatus(ctx, ids, status)
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "CommentService.DeleteComment")
+	defer endSpan(span, &err)
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *commentService) visiblePost(ctx context.Context, slug string) (*model.BlogPost, error) {
+	post, err := s.blogRepo.GetBySlug(ctx, slug)
+	if err != nil || post == nil {
+		return nil, err
+	}
+	if !post.Published || post.PublishAt.After(time.Now()) {
+		return nil, nil
+	}
+	return post, nil
+}
+
+// setAuthor takes name and email from the account of a logged in author, and
+// from the request otherwise
+func (s *commentService) setAuthor(ctx context.Context, comment *model.Comment, req model.CommentRequest, userID *uint) error {
+	if userID != nil {
+		user, err := s.userRepo.GetByID(ctx, *userID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !user.Active {
+			return newValidationError("account not found")
+		}
+		comment.UserID = &user.ID
+		comment.AuthorName = user.Username
+		comment.AuthorEmail = user.Email
+		return nil
+	}
+
+	comment.AuthorName = strings.TrimSpace(req.AuthorName)
+	if n := utf8.RuneCountInString(comment.AuthorName); n == 0 || n > commentMaxNameLength {
+		return newValidationError(fmt.Sprintf("author_name must be between 1 and %d characters", commentMaxNameLength))
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(req.AuthorEmail))
+	if err != nil {
+		return newValidationError("author_email must be a valid email address")
+	}
+	comment.AuthorEmail = address.Address
+	return nil
+}
+
+// spamReason runs the built-in checks on a guest comment and describes the first
+// one that fails, or returns an empty string
+func (s *commentService) spamReason(comment *model.Comment, honeypot string) string {
+	if strings.TrimSpace(honeypot) != "" {
+		return "honeypot field filled in"
+	}
+	if strings.TrimSpace(comment.UserAgent) == "" {
+		return "no user agent"
+	}
+	if commentLinkRe.MatchString(comment.AuthorName) {
+		return "link in author name"
+	}
+	if links := len(commentLinkRe.FindAllString(comment.Content, -1)); links > s.cfg.MaxLinks {
+		return fmt.Sprintf("%d links", links)
+	}
+
+	text := strings.ToLower(strings.Join([]string{comment.AuthorName, comment.AuthorEmail, comment.AuthorURL, comment.Content}, " "))
+	for _, word := range s.cfg.Blocklist {
+		if strings.Contains(text, word) {
+			return fmt.Sprintf("blocked word %q", word)
+		}
+	}
+	return ""
+}
+
+// buildCommentTree nests replies under their parents. Replies whose parent is
+// not approved are left out, so the count only includes reachable comments.
+func buildCommentTree(comments []model.Comment) ([]model.PublicComment, int) {
+	var roots []model.Comment
+	replies := make(map[uint][]model.Comment)
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		}
+	}
+
+	count := 0
+	var build func(level []model.Comment) []model.PublicComment
+	build = func(level []model.Comment) []model.PublicComment {
+		nodes := make([]model.PublicComment, 0, len(level))
+		for _, comment := range level {
+			count++
+			nodes = append(nodes, model.PublicComment{
+				ID:         comment.ID,
+				ParentID:   comment.ParentID,
+				AuthorName: comment.AuthorName,
+				AuthorURL:  comment.AuthorURL,
+				Registered: comment.UserID != nil,
+				Content:    comment.Content,
+				CreatedAt:  comment.CreatedAt,
+				Replies:    build(replies[comment.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots), count
+}
+
+func isCommentStatus(status string) bool {
+	switch status {
+	case model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusRejected, model.CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// hashCommentIP keeps addresses comparable for rate limiting without storing them
+func hashCommentIP(secret, ip string) string {
+	return visitorHash(secret, ip)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"github.com/lutestringamend/perwebbe/internal/event"
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/internal/repository"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
@@ -9,28 +11,46 @@ import (
 
 type contactService struct {
 	repo repository.ContactRepository
+	bus  EventBus
 }
 
-func NewContactService(repo repository.ContactRepository) ContactService {
-	return &contactService{repo: repo}
+func NewContactService(repo repository.ContactRepository, bus EventBus) ContactService {
+	return &contactService{repo: repo, bus: bus}
 }
 
-func (s *contactService) CreateContact(submission *model.ContactSubmission) error {
-	return s.repo.Create(submission)
+func (s *contactService) CreateContact(ctx context.Context, submission *model.ContactSubmission) (err error) {
+	ctx, span := tracer.Start(ctx, "ContactService.CreateContact")
+	defer endSpan(span, &err)
+
+	if err := s.repo.Create(ctx, submission, event.ContactReceived{Submission: submission}); err != nil {
+		return err
+	}
+
+	s.bus.Wake()
+	return nil
 }
 
-func (s *contactService) GetAllContacts(page, pageSize int) (*paging.Paginator, error) {
-	return s.repo.GetAll(page, pageSize)
+func (s *contactService) GetAllContacts(ctx context.Context, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "ContactService.GetAllContacts")
+	defer endSpan(span, &err)
+
+	return s.repo.GetAll(ctx, page, pageSize)
 }
 
-func (s *contactService) MarkContactAsRead(id uint) error {
-	return s.repo.MarkAsRead(id)
+func (s *contactService) MarkContactAsRead(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "ContactService.MarkContactAsRead")
+	defer endSpan(span, &err)
+
+	return s.repo.MarkAsRead(ctx, id)
 }
 
-func (s *contactService) DeleteContact(id uint) error {
-	return s.repo.Delete(id)
+func (s *contactService) DeleteContact(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "ContactService.DeleteContact")
+	defer endSpan(span, &err)
+
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *contactService) GetBlogBaseQuery() *gorm.DB {
-	return s.repo.GetBaseQuery()
+func (s *contactService) GetBlogBaseQuery(ctx context.Context) *gorm.DB {
+	return s.repo.GetBaseQuery(ctx)
 }
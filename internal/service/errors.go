@@ -0,0 +1,15 @@
+package service
+
+// ValidationError is returned when a service rejects its input before
+// touching the repository, so handlers can answer with 400 instead of 500
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func newValidationError(message string) error {
+	return &ValidationError{Message: message}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+// healthCheckTimeout bounds each readiness check, so a hanging database fails the probe instead of stalling it
+const healthCheckTimeout = time.Second * 2
+
+type healthService struct {
+	repo     repository.HealthRepository
+	version  model.VersionInfo
+	draining atomic.Bool
+}
+
+func NewHealthService(repo repository.HealthRepository, version model.VersionInfo) HealthService {
+	return &healthService{repo: repo, version: version}
+}
+
+// Ready checks that the database answers and its schema is current. A
+// draining server is never ready, so load balancers stop sending it requests.
+func (s *healthService) Ready(ctx context.Context) *model.HealthReport {
+	if s.draining.Load() {
+		return &model.HealthReport{
+			Status: model.HealthFail,
+			Checks: []model.HealthCheck{{Name: "server", Status: model.HealthFail, Error: "shutting down"}},
+		}
+	}
+
+	report := &model.HealthReport{Status: model.HealthOK}
+	report.Checks = append(report.Checks, s.check(ctx, "database", s.repo.Ping))
+	report.Checks = append(report.Checks, s.check(ctx, "migrations", func(ctx context.Context) error {
+		pending, err := s.repo.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}))
+
+	for _, check := range report.Checks {
+		if check.Status != model.HealthOK {
+			report.Status = model.HealthFail
+		}
+	}
+	return report
+}
+
+func (s *healthService) check(ctx context.Context, name string, fn func(ctx context.Context) error) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	check := model.HealthCheck{Name: name, Status: model.HealthOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Status = model.HealthFail
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// SetDraining makes the readiness check fail from now on
+func (s *healthService) SetDraining() {
+	s.draining.Store(true)
+}
+
+func (s *healthService) Version() model.VersionInfo {
+	return s.version
+}
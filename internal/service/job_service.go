@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+)
+
+type jobService struct {
+	repo repository.JobRepository
+}
+
+func NewJobService(repo repository.JobRepository) JobService {
+	return &jobService{repo: repo}
+}
+
+func (s *jobService) GetJobs(ctx context.Context, filter model.JobFilter, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "JobService.GetJobs")
+	defer endSpan(span, &err)
+
+	switch filter.Status {
+	case "", model.JobPending, model.JobRunning, model.JobSucceeded, model.JobDead:
+	default:
+		return nil, newValidationError("status must be pending, running, succeeded or dead")
+	}
+	return s.repo.GetAll(ctx, filter, page, pageSize)
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uint) (_ *model.Job, err error) {
+	ctx, span := tracer.Start(ctx, "JobService.GetJob")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *jobService) GetStats(ctx context.Context) (_ map[string]int64, err error) {
+	ctx, span := tracer.Start(ctx, "JobService.GetStats")
+	defer endSpan(span, &err)
+
+	return s.repo.CountByStatus(ctx)
+}
+
+// RetryJob queues a job again right away with a fresh set of attempts. It is
+// meant for dead jobs, but also runs a pending or succeeded job once more.
+func (s *jobService) RetryJob(ctx context.Context, id uint) (_ *model.Job, err error) {
+	ctx, span := tracer.Start(ctx, "JobService.RetryJob")
+	defer endSpan(span, &err)
+
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	if job.Status == model.JobRunning {
+		return nil, newValidationError("job is running")
+	}
+
+	job.Status = model.JobPending
+	job.Attempts = 0
+	job.RunAt = time.Now()
+	job.LockedUntil = nil
+	job.FinishedAt = nil
+	return job, s.repo.Update(ctx, job)
+}
+
+func (s *jobService) DeleteJob(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "JobService.DeleteJob")
+	defer endSpan(span, &err)
+
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil || job == nil {
+		return err
+	}
+	if job.Status == model.JobRunning {
+		return newValidationError("job is running")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *jobService) GetSchedules(ctx context.Context) (_ []model.JobSchedule, err error) {
+	ctx, span := tracer.Start(ctx, "JobService.GetSchedules")
+	defer endSpan(span, &err)
+
+	return s.repo.GetSchedules(ctx)
+}
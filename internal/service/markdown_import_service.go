@@ -0,0 +1,303 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/pkg/frontmatter"
+)
+
+// Caps on what a zip may unpack to, as the compressed size says little about it
+const (
+	markdownMaxFileSize  = 4 << 20
+	markdownMaxTotalSize = 64 << 20
+)
+
+// Jekyll-style file names carry the publish date, e.g. 2021-03-04-hello-world.md
+var datedFilenameRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// markdownFrontMatter lists the supported front matter keys
+type markdownFrontMatter struct {
+	Title       string                 `yaml:"title"`
+	Slug        string                 `yaml:"slug"`
+	Date        string                 `yaml:"date"`
+	Tags        frontmatter.StringList `yaml:"tags"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Image       string                 `yaml:"image"`
+	Draft       bool                   `yaml:"draft"`
+}
+
+type markdownImportService struct {
+	blogService BlogService
+}
+
+func NewMarkdownImportService(blogService BlogService) MarkdownImportService {
+	return &markdownImportService{blogService: blogService}
+}
+
+// Import creates a post per file, or updates the post that already has its slug.
+// A failing file is reported and does not stop the others.
+func (s *markdownImportService) Import(ctx context.Context, files []model.MarkdownFile, dryRun bool) (_ *model.MarkdownImportReport, err error) {
+	ctx, span := tracer.Start(ctx, "MarkdownImportService.Import")
+	defer endSpan(span, &err)
+
+	if len(files) == 0 {
+		return nil, newValidationError("no Markdown files found")
+	}
+
+	report := &model.MarkdownImportReport{DryRun: dryRun, Files: make([]model.MarkdownFileResult, 0, len(files))}
+	seen := make(map[string]string)
+
+	for _, file := range files {
+		result := model.MarkdownFileResult{File: file.Name}
+
+		post, err := parseMarkdownPost(file)
+		if post != nil {
+			result.Slug = post.Slug
+		}
+
+		switch {
+		case err != nil:
+			result.Status, result.Error = model.MarkdownStatusInvalid, err.Error()
+		case seen[post.Slug] != "":
+			result.Status = model.MarkdownStatusConflict
+			result.Error = fmt.Sprintf("slug is also used by %s", seen[post.Slug])
+		default:
+			seen[post.Slug] = file.Name
+			result.Status, err = s.importPost(ctx, post, dryRun)
+			if err != nil {
+				result.Error = err.Error()
+			}
+		}
+
+		switch result.Status {
+		case model.MarkdownStatusCreated:
+			report.Created++
+		case model.MarkdownStatusUpdated:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+		report.Files = append(report.Files, result)
+	}
+
+	return report, nil
+}
+
+func (s *markdownImportService) importPost(ctx context.Context, post *model.BlogPost, dryRun bool) (string, error) {
+	existing, err := s.blogService.GetBlogBySlug(ctx, post.Slug)
+	if err != nil {
+		return model.MarkdownStatusFailed, err
+	}
+
+	if existing == nil {
+		// Soft-deleted posts keep their slug in the unique index
+		var deleted int64
+		if err := s.blogService.GetBlogBaseQuery(ctx).Unscoped().Where("slug = ?", post.Slug).Count(&deleted).Error; err != nil {
+			return model.MarkdownStatusFailed, err
+		}
+		if deleted > 0 {
+			return model.MarkdownStatusConflict, fmt.Errorf("slug belongs to a deleted post")
+		}
+
+		if post.PublishAt.IsZero() {
+			post.PublishAt = time.Now()
+		}
+		if !dryRun {
+			if err := s.blogService.CreateBlog(ctx, post); err != nil {
+				return model.MarkdownStatusFailed, err
+			}
+		}
+		return model.MarkdownStatusCreated, nil
+	}
+
+	post.ID = existing.ID
+	post.CreatedAt = existing.CreatedAt
+	// Not part of the front matter
+	post.SEO = existing.SEO
+	post.SeriesID, post.SeriesPosition = existing.SeriesID, existing.SeriesPosition
+	post.CommentsClosed = existing.CommentsClosed
+	if post.PublishAt.IsZero() {
+		post.PublishAt = existing.PublishAt
+	}
+	if !dryRun {
+		if err := s.blogService.UpdateBlog(ctx, post); err != nil {
+			return model.MarkdownStatusFailed, err
+		}
+	}
+	return model.MarkdownStatusUpdated, nil
+}
+
+// parseMarkdownPost maps the front matter and body of a file onto a BlogPost.
+// The slug falls back to the file name, or the directory name for index.md.
+func parseMarkdownPost(file model.MarkdownFile) (*model.BlogPost, error) {
+	var meta markdownFrontMatter
+	body, err := frontmatter.Unmarshal(file.Content, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	name := strings.TrimSuffix(path.Base(file.Name), path.Ext(file.Name))
+	if name == "index" {
+		name = path.Base(path.Dir(file.Name))
+	}
+	var filenameDate string
+	if match := datedFilenameRe.FindStringSubmatch(name); match != nil {
+		filenameDate, name = match[1], match[2]
+	}
+
+	post := &model.BlogPost{
+		Title:     strings.TrimSpace(meta.Title),
+		Content:   strings.TrimSpace(string(body)),
+		Summary:   strings.TrimSpace(meta.Summary),
+		ImageURL:  strings.TrimSpace(meta.Image),
+		Published: !meta.Draft,
+		Slug:      normalizeSlug(meta.Slug),
+	}
+	if post.Summary == "" {
+		post.Summary = strings.TrimSpace(meta.Description)
+	}
+	if post.Slug == "" {
+		post.Slug = slugify(name)
+	}
+	for _, tag := range meta.Tags {
+		post.Tags = append(post.Tags, model.Tag{Name: tag})
+	}
+
+	if !slugPattern.MatchString(post.Slug) {
+		return post, fmt.Errorf("slug %q may only contain lowercase letters, digits and dashes", post.Slug)
+	}
+	if post.Title == "" {
+		return post, fmt.Errorf("title is required")
+	}
+	if post.Content == "" {
+		return post, fmt.Errorf("content is empty")
+	}
+
+	date := strings.TrimSpace(meta.Date)
+	if date == "" {
+		date = filenameDate
+	}
+	if date != "" {
+		if post.PublishAt, err = parseFrontMatterDate(date); err != nil {
+			return post, err
+		}
+	}
+
+	return post, nil
+}
+
+func parseFrontMatterDate(value string) (time.Time, error) {
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func isMarkdownFile(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(base))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// ReadMarkdownDir collects the Markdown files below dir, sorted by path
+func ReadMarkdownDir(dir string) ([]model.MarkdownFile, error) {
+	var files []model.MarkdownFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !isMarkdownFile(rel) {
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, model.MarkdownFile{Name: rel, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// ReadMarkdownZip collects the Markdown files inside a zip archive, sorted by
+// path. Files past markdownMaxFileSize, or past markdownMaxTotalSize together,
+// fail the whole archive.
+func ReadMarkdownZip(r io.ReaderAt, size int64) ([]model.MarkdownFile, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, newValidationError("markdown upload is not a valid zip archive")
+	}
+
+	var files []model.MarkdownFile
+	var total int64
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || !isMarkdownFile(entry.Name) {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		// The sizes in the zip headers can lie, so the reads themselves are capped
+		content, err := io.ReadAll(io.LimitReader(rc, markdownMaxFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > markdownMaxFileSize {
+			return nil, newValidationError(fmt.Sprintf("%s is larger than %d bytes", entry.Name, markdownMaxFileSize))
+		}
+		if total += int64(len(content)); total > markdownMaxTotalSize {
+			return nil, newValidationError(fmt.Sprintf("markdown files in the archive are larger than %d bytes together", markdownMaxTotalSize))
+		}
+		files = append(files, model.MarkdownFile{Name: entry.Name, Content: content})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
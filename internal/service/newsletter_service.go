@@ -0,0 +1,447 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/mailer"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+)
+
+const (
+	newsletterResendDelay    = time.Minute * 10 // Confirmation emails are not resent more often
+	newsletterConfirmTTL     = time.Hour * 24 * 7
+	newsletterDefaultBatch   = 50
+	newsletterTokenBytes     = 32
+	newsletterConfirmSubject = "Confirm your subscription to %s"
+)
+
+var confirmTextTemplate = template.Must(template.New("confirm").Parse(`Please confirm that you want to receive new posts from {{.SiteTitle}}:
+
+{{.ConfirmURL}}
+
+If you did not ask for this, ignore this email and nothing will happen.
+`))
+
+var digestTextTemplate = template.Must(template.New("digest").Parse(`New on {{.SiteTitle}}
+{{range .Posts}}
+{{.Title}}
+{{if .Summary}}{{.Summary}}
+{{end}}{{.URL}}
+{{end}}
+--
+Unsubscribe: {{.UnsubscribeURL}}
+`))
+
+var digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f4f4f5;font-family:Helvetica,Arial,sans-serif;color:#18181b">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width:600px;margin:0 auto;background:#ffffff">
+<tr><td style="padding:24px"><h1 style="margin:0;font-size:20px">New on {{.SiteTitle}}</h1></td></tr>
+{{range .Posts}}<tr><td style="padding:0 24px 24px">
+{{if .ImageURL}}<a href="{{.URL}}"><img src="{{.ImageURL}}" alt="" width="552" style="display:block;width:100%;height:auto;margin-bottom:12px"></a>{{end}}
+<h2 style="margin:0 0 8px;font-size:18px"><a href="{{.URL}}" style="color:#18181b">{{.Title}}</a></h2>
+{{if .Summary}}<p style="margin:0 0 8px;line-height:1.5">{{.Summary}}</p>{{end}}
+<a href="{{.URL}}" style="color:#2563eb">Read more</a>
+</td></tr>
+{{end}}<tr><td style="padding:24px;font-size:12px;color:#71717a">
+You receive this email because you subscribed to {{.SiteTitle}}. <a href="{{.UnsubscribeURL}}" style="color:#71717a">Unsubscribe</a>
+</td></tr>
+</table>
+</body>
+</html>
+`))
+
+type digestPost struct {
+	Title    string
+	Summary  string
+	ImageURL string
+	URL      string
+}
+
+type newsletterService struct {
+	repo   repository.NewsletterRepository
+	mailer mailer.Mailer
+	jobs   JobQueue
+	cfg    config.NewsletterConfig
+	site   config.SiteConfig
+
+	sending sync.Mutex // Held while an issue is being sent
+}
+
+func NewNewsletterService(repo repository.NewsletterRepository, m mailer.Mailer, jobs JobQueue, cfg config.NewsletterConfig, site config.SiteConfig) NewsletterService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = newsletterDefaultBatch
+	}
+	return &newsletterService{repo: repo, mailer: m, jobs: jobs, cfg: cfg, site: site}
+}
+
+// Subscribe queues a confirmation link. It behaves the same whether or not the
+// address is already subscribed, so it cannot be used to probe the list.
+func (s *newsletterService) Subscribe(ctx context.Context, email string) (err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.Subscribe")
+	defer endSpan(span, &err)
+
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return newValidationError("email must be a valid email address")
+	}
+	email = strings.ToLower(address.Address)
+
+	subscriber, err := s.repo.GetSubscriberByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch {
+	case subscriber == nil:
+		unsubscribeToken, err := newNewsletterToken()
+		if err != nil {
+			return err
+		}
+		subscriber = &model.Subscriber{Email: email, UnsubscribeToken: unsubscribeToken}
+	case subscriber.Status == model.SubscriberActive:
+		return nil
+	case subscriber.ConfirmationSentAt != nil && now.Sub(*subscriber.ConfirmationSentAt) < newsletterResendDelay:
+		return nil
+	}
+
+	confirmToken, err := newNewsletterToken()
+	if err != nil {
+		return err
+	}
+	subscriber.Status = model.SubscriberPending
+	subscriber.ConfirmToken = confirmToken
+	subscriber.ConfirmationSentAt = &now
+
+	if subscriber.ID == 0 {
+		err = s.repo.CreateSubscriber(ctx, subscriber)
+	} else {
+		err = s.repo.UpdateSubscriber(ctx, subscriber)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.jobs.Enqueue(ctx, model.JobKindNewsletterConfirmation, subscriber.ID)
+	return err
+}
+
+// SendConfirmation mails the confirmation link to a pending subscriber. It runs
+// as a job, so failed sends are retried.
+func (s *newsletterService) SendConfirmation(ctx context.Context, subscriberID uint) (err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.SendConfirmation")
+	defer endSpan(span, &err)
+
+	subscriber, err := s.repo.GetSubscriberByID(ctx, subscriberID)
+	if err != nil {
+		return err
+	}
+	if subscriber == nil || subscriber.Status != model.SubscriberPending || subscriber.ConfirmToken == "" {
+		return nil
+	}
+
+	var text bytes.Buffer
+	err = confirmTextTemplate.Execute(&text, map[string]string{
+		"SiteTitle":  s.site.Title,
+		"ConfirmURL": s.apiURL("/api/newsletter/confirm", subscriber.ConfirmToken),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      subscriber.Email,
+		Subject: fmt.Sprintf(newsletterConfirmSubject, s.site.Title),
+		Text:    text.String(),
+	})
+}
+
+// Confirm activates the subscriber of a confirmation token; false means the
+// token is unknown or expired
+func (s *newsletterService) Confirm(ctx context.Context, token string) (_ bool, err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.Confirm")
+	defer endSpan(span, &err)
+
+	if token == "" {
+		return false, nil
+	}
+
+	subscriber, err := s.repo.GetSubscriberByConfirmToken(ctx, token)
+	if err != nil || subscriber == nil {
+		return false, err
+	}
+	if subscriber.ConfirmationSentAt == nil || time.Since(*subscriber.ConfirmationSentAt) > newsletterConfirmTTL {
+		return false, nil
+	}
+
+	now := time.Now()
+	subscriber.Status = model.SubscriberActive
+	subscriber.ConfirmToken = ""
+	subscriber.ConfirmedAt = &now
+	subscriber.UnsubscribedAt = nil
+	return true, s.repo.UpdateSubscriber(ctx, subscriber)
+}
+
+// Unsubscribe stops all mail to the subscriber of an unsubscribe token; false
+// means the token is unknown
+func (s *newsletterService) Unsubscribe(ctx context.Context, token string) (_ bool, err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.Unsubscribe")
+	defer endSpan(span, &err)
+
+	if token == "" {
+		return false, nil
+	}
+
+	subscriber, err := s.repo.GetSubscriberByUnsubscribeToken(ctx, token)
+	if err != nil || subscriber == nil {
+		return false, err
+	}
+	if subscriber.Status == model.SubscriberUnsubscribed {
+		return true, nil
+	}
+
+	now := time.Now()
+	subscriber.Status = model.SubscriberUnsubscribed
+	subscriber.ConfirmToken = ""
+	subscriber.UnsubscribedAt = &now
+	return true, s.repo.UpdateSubscriber(ctx, subscriber)
+}
+
+func (s *newsletterService) GetSubscribers(ctx context.Context, status string, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.GetSubscribers")
+	defer endSpan(span, &err)
+
+	switch status {
+	case "", model.SubscriberPending, model.SubscriberActive, model.SubscriberUnsubscribed:
+	default:
+		return nil, newValidationError("status must be pending, active or unsubscribed")
+	}
+	return s.repo.GetSubscribers(ctx, status, page, pageSize)
+}
+
+// AddSubscriber subscribes an address without confirmation, for admins moving an existing list
+func (s *newsletterService) AddSubscriber(ctx context.Context, email string) (_ *model.Subscriber, err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.AddSubscriber")
+	defer endSpan(span, &err)
+
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return nil, newValidationError("email must be a valid email address")
+	}
+	email = strings.ToLower(address.Address)
+
+	subscriber, err := s.repo.GetSubscriberByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if subscriber == nil {
+		unsubscribeToken, err := newNewsletterToken()
+		if err != nil {
+			return nil, err
+		}
+		subscriber = &model.Subscriber{Email: email, Status: model.SubscriberActive, UnsubscribeToken: unsubscribeToken, ConfirmedAt: &now}
+		return subscriber, s.repo.CreateSubscriber(ctx, subscriber)
+	}
+
+	subscriber.Status = model.SubscriberActive
+	subscriber.ConfirmToken = ""
+	subscriber.ConfirmedAt = &now
+	subscriber.UnsubscribedAt = nil
+	return subscriber, s.repo.UpdateSubscriber(ctx, subscriber)
+}
+
+func (s *newsletterService) DeleteSubscriber(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.DeleteSubscriber")
+	defer endSpan(span, &err)
+
+	return s.repo.DeleteSubscriber(ctx, id)
+}
+
+func (s *newsletterService) GetIssues(ctx context.Context, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.GetIssues")
+	defer endSpan(span, &err)
+
+	return s.repo.GetIssues(ctx, page, pageSize)
+}
+
+// TriggerDigest sends a digest of the posts published since the last one in the
+// background, unless an issue is already being sent
+func (s *newsletterService) TriggerDigest(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.TriggerDigest")
+	defer endSpan(span, &err)
+
+	if !s.sending.TryLock() {
+		return newValidationError("a digest is already being sent")
+	}
+
+	go func() {
+		defer s.sending.Unlock()
+		if err := s.sendDigest(context.Background()); err != nil {
+			slog.ErrorContext(ctx, "newsletter digest failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// SendDigest sends a digest as the scheduled job does, unless an issue is
+// already being sent; an interrupted issue is finished first
+func (s *newsletterService) SendDigest(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "NewsletterService.SendDigest")
+	defer endSpan(span, &err)
+
+	if !s.sending.TryLock() {
+		return nil
+	}
+	defer s.sending.Unlock()
+	return s.sendDigest(ctx)
+}
+
+// sendDigest finishes an interrupted issue, or creates one for the new posts,
+// and mails it to every active subscriber. It must hold s.sending.
+func (s *newsletterService) sendDigest(ctx context.Context) error {
+	issue, err := s.repo.GetOpenIssue(ctx)
+	if err != nil {
+		return err
+	}
+
+	if issue == nil {
+		now := time.Now()
+		posts, err := s.repo.GetDigestPosts(ctx, now.Add(-s.cfg.MaxPostAge), now)
+		if err != nil || len(posts) == 0 {
+			return err
+		}
+
+		issue = &model.NewsletterIssue{Subject: s.digestSubject(posts), Status: model.NewsletterIssueSending, Posts: posts}
+		if err := s.repo.CreateIssue(ctx, issue); err != nil {
+			return err
+		}
+	}
+
+	posts := make([]digestPost, len(issue.Posts))
+	for i, post := range issue.Posts {
+		posts[i] = digestPost{Title: post.Title, Summary: post.Summary, ImageURL: post.ImageURL, URL: s.site.PostURL(post.Slug)}
+	}
+
+	for {
+		subscribers, err := s.repo.NextActiveSubscribers(ctx, issue.LastSubscriberID, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range subscribers {
+			if err := ctx.Err(); err != nil {
+				return s.saveProgress(ctx, issue, err)
+			}
+
+			msg, err := s.digestMessage(issue, posts, &subscribers[i])
+			if err != nil {
+				return s.saveProgress(ctx, issue, err)
+			}
+			if err := s.mailer.Send(ctx, msg); err != nil {
+				slog.WarnContext(ctx, "newsletter sending failed", "issue_id", issue.ID, "subscriber_id", subscribers[i].ID, "error", err)
+				issue.FailedCount++
+			} else {
+				issue.SentCount++
+			}
+			issue.LastSubscriberID = subscribers[i].ID
+		}
+
+		if len(subscribers) < s.cfg.BatchSize {
+			break
+		}
+		if err := s.repo.UpdateIssueProgress(ctx, issue); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.cfg.BatchPause):
+		}
+	}
+
+	now := time.Now()
+	issue.Status = model.NewsletterIssueSent
+	issue.CompletedAt = &now
+	slog.InfoContext(ctx, "newsletter issue sent", "issue_id", issue.ID, "sent", issue.SentCount, "failed", issue.FailedCount)
+	return s.repo.UpdateIssueProgress(ctx, issue)
+}
+
+// saveProgress stores how far an issue got before err stopped it, even when
+// that was the cancellation of ctx
+func (s *newsletterService) saveProgress(ctx context.Context, issue *model.NewsletterIssue, err error) error {
+	if saveErr := s.repo.UpdateIssueProgress(context.WithoutCancel(ctx), issue); saveErr != nil {
+		slog.ErrorContext(ctx, "newsletter progress was not saved", "issue_id", issue.ID, "error", saveErr)
+	}
+	return err
+}
+
+func (s *newsletterService) digestSubject(posts []model.BlogPost) string {
+	if len(posts) == 1 {
+		return posts[0].Title
+	}
+	return fmt.Sprintf("%d new posts on %s", len(posts), s.site.Title)
+}
+
+func (s *newsletterService) digestMessage(issue *model.NewsletterIssue, posts []digestPost, subscriber *model.Subscriber) (mailer.Message, error) {
+	unsubscribeURL := s.apiURL("/api/newsletter/unsubscribe", subscriber.UnsubscribeToken)
+	data := map[string]interface{}{
+		"SiteTitle":      s.site.Title,
+		"Posts":          posts,
+		"UnsubscribeURL": unsubscribeURL,
+	}
+
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, data); err != nil {
+		return mailer.Message{}, err
+	}
+	if err := digestHTMLTemplate.Execute(&html, data); err != nil {
+		return mailer.Message{}, err
+	}
+
+	return mailer.Message{
+		To:      subscriber.Email,
+		Subject: issue.Subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			// One-click unsubscribe as described in RFC 8058
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func (s *newsletterService) apiURL(path, token string) string {
+	return s.cfg.APIURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func newNewsletterToken() (string, error) {
+	return randomToken(newsletterTokenBytes)
+}
+
+// randomToken returns size random bytes, hex encoded
+func randomToken(size int) (string, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
@@ -1,40 +1,200 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/event"
 	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/internal/repository"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
 	"gorm.io/gorm"
 )
 
+// isrcPattern matches a hyphen-less ISRC: country, registrant, year, designation
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{2}[0-9]{5}$`)
+
 type portfolioService struct {
-	repo repository.PortfolioRepository
+	repo     repository.PortfolioRepository
+	typeRepo repository.ProjectTypeRepository
+	bus      EventBus
 }
 
-func NewPortfolioService(repo repository.PortfolioRepository) PortfolioService {
-	return &portfolioService{repo: repo}
+func NewPortfolioService(repo repository.PortfolioRepository, typeRepo repository.ProjectTypeRepository, bus EventBus) PortfolioService {
+	return &portfolioService{repo: repo, typeRepo: typeRepo, bus: bus}
 }
 
-func (s *portfolioService) CreateProject(project *model.PortfolioProject) error {
-	return s.repo.Create(project)
+func (s *portfolioService) CreateProject(ctx context.Context, project *model.PortfolioProject) (err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.CreateProject")
+	defer endSpan(span, &err)
+
+	if err := s.validateProject(ctx, project); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, project, event.ProjectCreated{Project: project}); err != nil {
+		return err
+	}
+
+	s.bus.Wake()
+	return nil
 }
 
-func (s *portfolioService) GetProjectByID(id uint) (*model.PortfolioProject, error) {
-	return s.repo.GetByID(id)
+func (s *portfolioService) GetProjectByID(ctx context.Context, id uint) (_ *model.PortfolioProject, err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.GetProjectByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *portfolioService) GetAllProjects(projectType string, page, pageSize int) (*paging.Paginator, error) {
-	return s.repo.GetAll(projectType, page, pageSize)
+func (s *portfolioService) GetAllProjects(ctx context.Context, filter model.ProjectFilter, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.GetAllProjects")
+	defer endSpan(span, &err)
+
+	filter.ProjectType = normalizeSlug(filter.ProjectType)
+
+	switch filter.Sort {
+	case "", model.ProjectSortCreatedAt, model.ProjectSortStartDate, model.ProjectSortEndDate,
+		model.ProjectSortTitle, model.ProjectSortManual, model.ProjectSortReactions:
+	default:
+		return nil, newValidationError(fmt.Sprintf("unknown sort option %q", filter.Sort))
+	}
+
+	return s.repo.GetAll(ctx, filter, page, pageSize)
 }
 
-func (s *portfolioService) UpdateProject(project *model.PortfolioProject) error {
-	return s.repo.Update(project)
+func (s *portfolioService) UpdateProject(ctx context.Context, project *model.PortfolioProject) (err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.UpdateProject")
+	defer endSpan(span, &err)
+
+	if err := s.validateProject(ctx, project); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, project, event.ProjectUpdated{Project: project}); err != nil {
+		return err
+	}
+
+	s.bus.Wake()
+	return nil
 }
 
-func (s *portfolioService) DeleteProject(id uint) error {
-	return s.repo.Delete(id)
+// ReorderProjects assigns manual sort positions following the order of ids;
+// projects left out keep their relative order after the listed ones
+func (s *portfolioService) ReorderProjects(ctx context.Context, ids []uint) (err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.ReorderProjects")
+	defer endSpan(span, &err)
+
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return newValidationError(fmt.Sprintf("project %d is listed more than once", id))
+		}
+		seen[id] = true
+	}
+
+	count, err := s.repo.CountByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return newValidationError("some of the listed projects do not exist")
+	}
+
+	return s.repo.Reorder(ctx, ids)
 }
 
-func (s *portfolioService) GetBlogBaseQuery() *gorm.DB {
-	return s.repo.GetBaseQuery()
+func (s *portfolioService) DeleteProject(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "PortfolioService.DeleteProject")
+	defer endSpan(span, &err)
+
+	project, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var events []model.DomainEvent
+	if project != nil {
+		events = append(events, event.ProjectDeleted{Project: project})
+	}
+
+	if err := s.repo.Delete(ctx, id, events...); err != nil {
+		return err
+	}
+
+	s.bus.Wake()
+	return nil
+}
+
+func (s *portfolioService) GetBlogBaseQuery(ctx context.Context) *gorm.DB {
+	return s.repo.GetBaseQuery(ctx)
+}
+
+func (s *portfolioService) validateProject(ctx context.Context, project *model.PortfolioProject) error {
+	project.ProjectType = normalizeSlug(project.ProjectType)
+	if project.ProjectType == "" {
+		return newValidationError("project type is required")
+	}
+
+	projectType, err := s.typeRepo.GetBySlug(ctx, project.ProjectType)
+	if err != nil {
+		return err
+	}
+	if projectType == nil {
+		return newValidationError(fmt.Sprintf("unknown project type %q", project.ProjectType))
+	}
+
+	if project.ProjectType == model.ProjectTypeMusic {
+		if project.Music == nil {
+			return newValidationError("music projects require music metadata")
+		}
+		return validateMusic(project.Music)
+	}
+	if project.Music != nil {
+		return newValidationError("music metadata is only allowed on music projects")
+	}
+	return nil
+}
+
+func validateMusic(music *model.MusicRelease) error {
+	if len(music.Tracks) == 0 {
+		return newValidationError("music projects require at least one track")
+	}
+
+	for i := range music.Tracks {
+		track := &music.Tracks[i]
+		if strings.TrimSpace(track.Title) == "" {
+			return newValidationError(fmt.Sprintf("track %d: title is required", i+1))
+		}
+		if track.DurationSeconds < 0 {
+			return newValidationError(fmt.Sprintf("track %d: duration must not be negative", i+1))
+		}
+		if track.ISRC != "" {
+			track.ISRC = strings.ToUpper(strings.ReplaceAll(track.ISRC, "-", ""))
+			if !isrcPattern.MatchString(track.ISRC) {
+				return newValidationError(fmt.Sprintf("track %d: invalid ISRC %q", i+1, track.ISRC))
+			}
+		}
+		if track.Position == 0 {
+			track.Position = i + 1
+		}
+	}
+
+	for i, credit := range music.Credits {
+		if strings.TrimSpace(credit.Name) == "" || strings.TrimSpace(credit.Role) == "" {
+			return newValidationError(fmt.Sprintf("credit %d: name and role are required", i+1))
+		}
+	}
+
+	for i, link := range music.StreamingLinks {
+		if strings.TrimSpace(link.Platform) == "" {
+			return newValidationError(fmt.Sprintf("streaming link %d: platform is required", i+1))
+		}
+		if u, err := url.ParseRequestURI(link.URL); err != nil || u.Host == "" {
+			return newValidationError(fmt.Sprintf("streaming link %d: invalid URL", i+1))
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/githost"
+)
+
+type projectImportService struct {
+	registry       *githost.Registry
+	portfolioRepo  repository.PortfolioRepository
+	technologyRepo repository.TechnologyRepository
+}
+
+func NewProjectImportService(registry *githost.Registry, portfolioRepo repository.PortfolioRepository, technologyRepo repository.TechnologyRepository) ProjectImportService {
+	return &projectImportService{
+		registry:       registry,
+		portfolioRepo:  portfolioRepo,
+		technologyRepo: technologyRepo,
+	}
+}
+
+// DraftFromRepository builds an unsaved coding project from the repository
+// metadata, for an admin to review before creating it
+func (s *projectImportService) DraftFromRepository(ctx context.Context, repoURL string) (_ *model.PortfolioProject, _ *githost.Repository, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectImportService.DraftFromRepository")
+	defer endSpan(span, &err)
+
+	if _, _, err := githost.ParseRepoURL(repoURL); err != nil {
+		return nil, nil, newValidationError(err.Error())
+	}
+
+	repo, err := s.registry.Fetch(ctx, repoURL)
+	if err != nil {
+		if errors.Is(err, githost.ErrUnsupportedHost) {
+			return nil, nil, newValidationError(err.Error())
+		}
+		return nil, nil, err
+	}
+
+	technologies, err := s.draftTechnologies(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	project := &model.PortfolioProject{
+		Title:        repositoryTitle(repo.FullName),
+		Description:  repo.Description,
+		ProjectType:  model.ProjectTypeCoding,
+		ProjectURL:   repo.Homepage,
+		RepoURL:      repoURL,
+		Stars:        repo.Stars,
+		RepoSyncedAt: &now,
+		Technologies: technologies,
+	}
+	if repo.WebURL != "" {
+		project.RepoURL = repo.WebURL
+	}
+	if !repo.LastCommitAt.IsZero() {
+		project.LastCommitAt = &repo.LastCommitAt
+	}
+
+	return project, repo, nil
+}
+
+// RefreshRepositoryStats updates stars and last commit of every project that
+// links a repository on a supported host, returning how many were refreshed.
+// A repository that cannot be fetched is logged and skipped, so only database
+// failures fail the refresh.
+func (s *projectImportService) RefreshRepositoryStats(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectImportService.RefreshRepositoryStats")
+	defer endSpan(span, &err)
+
+	projects, err := s.portfolioRepo.GetLinkedToRepositories(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+		if !s.registry.Supports(project.RepoURL) {
+			continue
+		}
+
+		repo, err := s.registry.Fetch(ctx, project.RepoURL)
+		if err != nil {
+			slog.WarnContext(ctx, "repository stats not refreshed", "project_id", project.ID, "repo_url", project.RepoURL, "error", err)
+			continue
+		}
+
+		var lastCommitAt *time.Time
+		if !repo.LastCommitAt.IsZero() {
+			lastCommitAt = &repo.LastCommitAt
+		}
+		if err := s.portfolioRepo.UpdateRepositoryStats(ctx, project.ID, repo.Stars, lastCommitAt, time.Now()); err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// draftTechnologies lists the repository languages plus the topics that name a
+// known technology, using the catalog's canonical spelling where there is one
+func (s *projectImportService) draftTechnologies(ctx context.Context, repo *githost.Repository) ([]string, error) {
+	var technologies []string
+	seen := make(map[string]bool)
+
+	add := func(name string, onlyKnown bool) error {
+		technology, err := s.technologyRepo.FindByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if technology != nil {
+			name = technology.Name
+		} else if onlyKnown {
+			return nil
+		}
+
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			technologies = append(technologies, name)
+		}
+		return nil
+	}
+
+	for _, language := range repo.Languages {
+		if err := add(language, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, topic := range repo.Topics {
+		if err := add(topic, true); err != nil {
+			return nil, err
+		}
+	}
+	return technologies, nil
+}
+
+// repositoryTitle turns "owner/my-cool_repo" into "My Cool Repo"
+func repositoryTitle(fullName string) string {
+	words := strings.FieldsFunc(path.Base(fullName), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugSeparatorRe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type projectTypeService struct {
+	repo repository.ProjectTypeRepository
+}
+
+func NewProjectTypeService(repo repository.ProjectTypeRepository) ProjectTypeService {
+	return &projectTypeService{repo: repo}
+}
+
+func (s *projectTypeService) CreateProjectType(ctx context.Context, projectType *model.ProjectType) (err error) {
+	ctx, span := tracer.Start(ctx, "ProjectTypeService.CreateProjectType")
+	defer endSpan(span, &err)
+
+	if err := s.validateProjectType(ctx, projectType); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, projectType)
+}
+
+func (s *projectTypeService) GetProjectTypeByID(ctx context.Context, id uint) (_ *model.ProjectType, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectTypeService.GetProjectTypeByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *projectTypeService) GetAllProjectTypes(ctx context.Context) (_ []model.ProjectType, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectTypeService.GetAllProjectTypes")
+	defer endSpan(span, &err)
+
+	return s.repo.GetAllWithCounts(ctx)
+}
+
+func (s *projectTypeService) UpdateProjectType(ctx context.Context, projectType *model.ProjectType) (err error) {
+	ctx, span := tracer.Start(ctx, "ProjectTypeService.UpdateProjectType")
+	defer endSpan(span, &err)
+
+	existing, err := s.repo.GetByID(ctx, projectType.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return newValidationError("project type not found")
+	}
+
+	if err := s.validateProjectType(ctx, projectType); err != nil {
+		return err
+	}
+	if isBuiltinProjectType(existing.Slug) && projectType.Slug != existing.Slug {
+		return newValidationError(fmt.Sprintf("the slug of the built-in %q type cannot be changed", existing.Slug))
+	}
+
+	projectType.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, projectType)
+}
+
+func (s *projectTypeService) DeleteProjectType(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "ProjectTypeService.DeleteProjectType")
+	defer endSpan(span, &err)
+
+	projectType, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if projectType == nil {
+		return nil
+	}
+	if isBuiltinProjectType(projectType.Slug) {
+		return newValidationError(fmt.Sprintf("the built-in %q type cannot be deleted", projectType.Slug))
+	}
+
+	count, err := s.repo.CountProjects(ctx, projectType.Slug)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return newValidationError(fmt.Sprintf("project type %q is still used by %d projects", projectType.Slug, count))
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *projectTypeService) validateProjectType(ctx context.Context, projectType *model.ProjectType) error {
+	projectType.Name = strings.TrimSpace(projectType.Name)
+	if projectType.Name == "" {
+		return newValidationError("name is required")
+	}
+
+	if strings.TrimSpace(projectType.Slug) == "" {
+		projectType.Slug = slugify(projectType.Name)
+	}
+	projectType.Slug = normalizeSlug(projectType.Slug)
+	if !slugPattern.MatchString(projectType.Slug) {
+		return newValidationError(fmt.Sprintf("invalid slug %q", projectType.Slug))
+	}
+
+	existing, err := s.repo.GetBySlug(ctx, projectType.Slug)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != projectType.ID {
+		return newValidationError(fmt.Sprintf("project type %q already exists", projectType.Slug))
+	}
+
+	return nil
+}
+
+// normalizeSlug canonicalizes a user supplied slug so that "Coding" and "coding" match
+func normalizeSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+// slugify turns a free-form title into a URL-safe slug
+func slugify(title string) string {
+	return strings.Trim(slugSeparatorRe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+func isBuiltinProjectType(slug string) bool {
+	return slug == model.ProjectTypeCoding || slug == model.ProjectTypeMusic
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+type reactionService struct {
+	repo          repository.ReactionRepository
+	blogRepo      repository.BlogRepository
+	portfolioRepo repository.PortfolioRepository
+	emojis        []string
+	allowed       map[string]bool
+	hashSecret    string
+}
+
+func NewReactionService(repo repository.ReactionRepository, blogRepo repository.BlogRepository, portfolioRepo repository.PortfolioRepository, cfg config.ReactionConfig) ReactionService {
+	s := &reactionService{repo: repo, blogRepo: blogRepo, portfolioRepo: portfolioRepo, emojis: cfg.Emojis, allowed: make(map[string]bool), hashSecret: cfg.HashSecret}
+	for _, emoji := range cfg.Emojis {
+		s.allowed[emoji] = true
+	}
+	return s
+}
+
+func (s *reactionService) Emojis() []string {
+	return s.emojis
+}
+
+// ReactToPost counts a reaction on a visible post, or returns nil when there is no such post
+func (s *reactionService) ReactToPost(ctx context.Context, slug, emoji, ip, userAgent string) (_ *model.ReactionSummary, err error) {
+	ctx, span := tracer.Start(ctx, "ReactionService.ReactToPost")
+	defer endSpan(span, &err)
+
+	if !s.allowed[emoji] {
+		return nil, newValidationError("unknown reaction")
+	}
+
+	post, err := s.blogRepo.GetBySlug(ctx, slug)
+	if err != nil || post == nil {
+		return nil, err
+	}
+	if !post.Published || post.PublishAt.After(time.Now()) {
+		return nil, nil
+	}
+	return s.react(ctx, model.ReactionContentPost, post.ID, emoji, ip, userAgent)
+}
+
+// ReactToProject counts a reaction on a project, or returns nil when there is no such project
+func (s *reactionService) ReactToProject(ctx context.Context, id uint, emoji, ip, userAgent string) (_ *model.ReactionSummary, err error) {
+	ctx, span := tracer.Start(ctx, "ReactionService.ReactToProject")
+	defer endSpan(span, &err)
+
+	if !s.allowed[emoji] {
+		return nil, newValidationError("unknown reaction")
+	}
+
+	project, err := s.portfolioRepo.GetByID(ctx, id)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	return s.react(ctx, model.ReactionContentProject, project.ID, emoji, ip, userAgent)
+}
+
+// react adds the reaction once per visitor and returns the updated counters.
+// Bots get the counters without being counted.
+func (s *reactionService) react(ctx context.Context, contentType string, contentID uint, emoji, ip, userAgent string) (*model.ReactionSummary, error) {
+	if !isBotUserAgent(userAgent) {
+		if _, err := s.repo.Add(ctx, contentType, contentID, emoji, reactionFingerprint(s.hashSecret, ip, userAgent)); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Summary(ctx, contentType, contentID)
+}
+
+// reactionFingerprint identifies a visitor without storing the address
+func reactionFingerprint(secret, ip, userAgent string) string {
+	return visitorHash(secret, ip+"\n"+userAgent)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+)
+
+const (
+	relatedPostsLimit = 4
+	maxRelatedPins    = 10
+	// relatedCacheTTL bounds how long a list can miss scheduled posts going live
+	relatedCacheTTL = time.Hour
+
+	relatedTagWeight     = 0.5
+	relatedTextWeight    = 0.35
+	relatedRecencyWeight = 0.15
+	// relatedRecencyDays is the age at which the recency score has halved
+	relatedRecencyDays = 90
+)
+
+// Words too common to say anything about what a post is about
+var relatedStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "this": true, "that": true,
+	"from": true, "your": true, "you": true, "are": true, "how": true, "what": true,
+	"why": true, "into": true, "our": true, "not": true, "but": true, "can": true,
+}
+
+type relatedCacheEntry struct {
+	posts   []model.RelatedPost
+	expires time.Time
+}
+
+// relatedCache holds computed related lists per post. Any content change
+// clears it whole, as one post takes part in the lists of many others.
+type relatedCache struct {
+	mu      sync.Mutex
+	entries map[uint]relatedCacheEntry
+}
+
+func newRelatedCache() *relatedCache {
+	return &relatedCache{entries: make(map[uint]relatedCacheEntry)}
+}
+
+func (c *relatedCache) get(postID uint) ([]model.RelatedPost, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[postID]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.posts, true
+}
+
+func (c *relatedCache) set(postID uint, posts []model.RelatedPost) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[postID] = relatedCacheEntry{posts: posts, expires: time.Now().Add(relatedCacheTTL)}
+}
+
+func (c *relatedCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[uint]relatedCacheEntry)
+}
+
+// relatedPosts returns the pinned posts followed by the best scoring others
+func (s *blogService) relatedPosts(ctx context.Context, post *model.BlogPost) ([]model.RelatedPost, error) {
+	if related, ok := s.related.get(post.ID); ok {
+		return related, nil
+	}
+
+	candidates, err := s.repo.GetRelatedCandidates(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	pins, err := s.repo.GetRelatedPins(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*model.BlogPost, len(candidates))
+	for i := range candidates {
+		byID[candidates[i].ID] = &candidates[i]
+	}
+
+	related := []model.RelatedPost{}
+	taken := map[uint]bool{post.ID: true}
+	for _, id := range pins {
+		// Pins to drafts or scheduled posts stay hidden until they go live
+		if candidate, ok := byID[id]; ok && !taken[id] {
+			taken[id] = true
+			related = append(related, toRelatedPost(candidate, true))
+		}
+	}
+
+	type scored struct {
+		post  *model.BlogPost
+		score float64
+	}
+	var ranked []scored
+	terms := relatedTerms(post)
+	now := time.Now()
+	for i := range candidates {
+		if taken[candidates[i].ID] {
+			continue
+		}
+		ranked = append(ranked, scored{&candidates[i], relatedScore(post, terms, &candidates[i], now)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].post.PublishAt.After(ranked[j].post.PublishAt)
+	})
+
+	for _, candidate := range ranked {
+		if len(related) >= relatedPostsLimit {
+			break
+		}
+		related = append(related, toRelatedPost(candidate.post, false))
+	}
+
+	s.related.set(post.ID, related)
+	return related, nil
+}
+
+func (s *blogService) SetRelatedPins(ctx context.Context, id uint, postIDs []uint) (err error) {
+	ctx, span := tracer.Start(ctx, "BlogService.SetRelatedPins")
+	defer endSpan(span, &err)
+
+	post, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return newValidationError("blog post not found")
+	}
+
+	if len(postIDs) > maxRelatedPins {
+		return newValidationError(fmt.Sprintf("at most %d related posts can be pinned", maxRelatedPins))
+	}
+	seen := make(map[uint]bool, len(postIDs))
+	for _, postID := range postIDs {
+		if postID == id {
+			return newValidationError("a post cannot be related to itself")
+		}
+		if seen[postID] {
+			return newValidationError(fmt.Sprintf("post %d is listed more than once", postID))
+		}
+		seen[postID] = true
+	}
+
+	if len(postIDs) > 0 {
+		count, err := s.repo.CountByIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		if count != int64(len(postIDs)) {
+			return newValidationError("some posts do not exist")
+		}
+	}
+
+	if err := s.repo.SetRelatedPins(ctx, id, postIDs); err != nil {
+		return err
+	}
+	s.related.clear()
+	return nil
+}
+
+// relatedScore blends tag overlap, text similarity of title and summary, and recency, each in [0, 1]
+func relatedScore(post *model.BlogPost, terms map[string]float64, candidate *model.BlogPost, now time.Time) float64 {
+	ageDays := now.Sub(candidate.PublishAt).Hours() / 24
+	if ageDays < 0 {
+		ageDays = 0
+	}
+	recency := 1 / (1 + ageDays/relatedRecencyDays)
+
+	return relatedTagWeight*tagSimilarity(post.Tags, candidate.Tags) +
+		relatedTextWeight*cosineSimilarity(terms, relatedTerms(candidate)) +
+		relatedRecencyWeight*recency
+}
+
+// tagSimilarity is the Jaccard index of two tag sets
+func tagSimilarity(a, b []model.Tag) float64 {
+	setA, setB := tagSet(a), tagSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for name := range setA {
+		if setB[name] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+func tagSet(tags []model.Tag) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[strings.ToLower(tag.Name)] = true
+	}
+	return set
+}
+
+// relatedTerms weighs the words of a post, counting title words twice
+func relatedTerms(post *model.BlogPost) map[string]float64 {
+	terms := make(map[string]float64)
+	add := func(text string, weight float64) {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if len([]rune(word)) < 3 || relatedStopWords[word] {
+				continue
+			}
+			terms[word] += weight
+		}
+	}
+	add(post.Title, 2)
+	add(post.Summary, 1)
+	return terms
+}
+
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		normA += weight * weight
+		dot += weight * b[term]
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func toRelatedPost(post *model.BlogPost, pinned bool) model.RelatedPost {
+	return model.RelatedPost{
+		ID:        post.ID,
+		Title:     post.Title,
+		Slug:      post.Slug,
+		Summary:   post.Summary,
+		ImageURL:  post.ImageURL,
+		PublishAt: post.PublishAt,
+		Pinned:    pinned,
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+type seriesService struct {
+	repo repository.SeriesRepository
+}
+
+func NewSeriesService(repo repository.SeriesRepository) SeriesService {
+	return &seriesService{repo: repo}
+}
+
+func (s *seriesService) CreateSeries(ctx context.Context, series *model.Series) (err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.CreateSeries")
+	defer endSpan(span, &err)
+
+	if err := s.validateSeries(ctx, series); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, series)
+}
+
+func (s *seriesService) GetSeriesByID(ctx context.Context, id uint) (_ *model.Series, err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.GetSeriesByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetSeriesBySlug returns a series with its visible parts; drafts and scheduled posts are left out
+func (s *seriesService) GetSeriesBySlug(ctx context.Context, slug string) (_ *model.Series, err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.GetSeriesBySlug")
+	defer endSpan(span, &err)
+
+	series, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil || series == nil {
+		return nil, err
+	}
+
+	series.Parts, err = s.repo.VisibleParts(ctx, series.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	series.PostCount = int64(len(series.Parts))
+	return series, nil
+}
+
+func (s *seriesService) GetAllSeries(ctx context.Context) (_ []model.Series, err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.GetAllSeries")
+	defer endSpan(span, &err)
+
+	return s.repo.GetAllWithCounts(ctx, time.Now())
+}
+
+func (s *seriesService) UpdateSeries(ctx context.Context, series *model.Series) (err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.UpdateSeries")
+	defer endSpan(span, &err)
+
+	existing, err := s.repo.GetByID(ctx, series.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return newValidationError("series not found")
+	}
+
+	if err := s.validateSeries(ctx, series); err != nil {
+		return err
+	}
+
+	series.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, series)
+}
+
+func (s *seriesService) SetSeriesPosts(ctx context.Context, id uint, postIDs []uint) (err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.SetSeriesPosts")
+	defer endSpan(span, &err)
+
+	series, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if series == nil {
+		return newValidationError("series not found")
+	}
+
+	seen := make(map[uint]bool, len(postIDs))
+	for _, postID := range postIDs {
+		if seen[postID] {
+			return newValidationError(fmt.Sprintf("post %d is listed more than once", postID))
+		}
+		seen[postID] = true
+	}
+
+	if len(postIDs) > 0 {
+		count, err := s.repo.CountPostsByIDs(ctx, postIDs)
+		if err != nil {
+			return err
+		}
+		if count != int64(len(postIDs)) {
+			return newValidationError("some posts do not exist")
+		}
+	}
+
+	return s.repo.SetPosts(ctx, id, postIDs)
+}
+
+func (s *seriesService) DeleteSeries(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.DeleteSeries")
+	defer endSpan(span, &err)
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *seriesService) validateSeries(ctx context.Context, series *model.Series) error {
+	series.Title = strings.TrimSpace(series.Title)
+	if series.Title == "" {
+		return newValidationError("title is required")
+	}
+
+	if strings.TrimSpace(series.Slug) == "" {
+		series.Slug = slugify(series.Title)
+	}
+	series.Slug = normalizeSlug(series.Slug)
+	if !slugPattern.MatchString(series.Slug) {
+		return newValidationError(fmt.Sprintf("invalid slug %q", series.Slug))
+	}
+
+	existing, err := s.repo.GetBySlug(ctx, series.Slug)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != series.ID {
+		return newValidationError(fmt.Sprintf("series %q already exists", series.Slug))
+	}
+
+	return nil
+}
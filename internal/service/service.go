@@ -1,46 +1,239 @@
 package service
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/lutestringamend/perwebbe/internal/job"
 	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/pkg/githost"
 	"github.com/lutestringamend/perwebbe/pkg/paging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
+// tracer records the span around each service method
+var tracer = otel.Tracer("github.com/lutestringamend/perwebbe/internal/service")
+
+// endSpan ends a service method's span, marking it failed when the method
+// returned an error; defer it with the address of the named error result
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// visitorHash keys a visitor's address with VISITOR_HASH_SECRET, so the stored
+// value cannot be matched against a list of all addresses
+func visitorHash(secret, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // BlogService defines methods for blog service
 type BlogService interface {
-	CreateBlog(post *model.BlogPost) error
-	GetBlogByID(id uint) (*model.BlogPost, error)
-	GetAllBlogs(page, pageSize int) (*paging.Paginator, error)
-	GetBlogBySlug(slug string) (*model.BlogPost, error)
-	UpdateBlog(post *model.BlogPost) error
-	DeleteBlog(id uint) error
-	GetBlogBaseQuery() *gorm.DB
+	CreateBlog(ctx context.Context, post *model.BlogPost) error
+	GetBlogByID(ctx context.Context, id uint) (*model.BlogPost, error)
+	GetAllBlogs(ctx context.Context, page, pageSize int, sort string) (*paging.Paginator, error)
+	GetPublishedBlogs(ctx context.Context, page, pageSize int) (*paging.Paginator, error)
+	GetBlogBySlug(ctx context.Context, slug string) (*model.BlogPost, error)
+	UpdateBlog(ctx context.Context, post *model.BlogPost) error
+	SetRelatedPins(ctx context.Context, id uint, postIDs []uint) error
+	DeleteBlog(ctx context.Context, id uint) error
+	PublishScheduled(ctx context.Context, payload model.BlogPublishPayload) error
+	GetBlogBaseQuery(ctx context.Context) *gorm.DB
+}
+
+// CommentService defines methods for blog comment service
+type CommentService interface {
+	GetThread(ctx context.Context, slug string) (*model.CommentThread, error)
+	Submit(ctx context.Context, slug string, req model.CommentRequest, userID *uint, ip, userAgent string) (*model.Comment, error)
+	GetComments(ctx context.Context, filter model.CommentFilter, page, pageSize int) (*paging.Paginator, error)
+	ModerateComments(ctx context.Context, ids []uint, status string) (int64, error)
+	DeleteComment(ctx context.Context, id uint) error
+}
+
+// ReactionService defines methods for anonymous reactions on posts and projects
+type ReactionService interface {
+	Emojis() []string
+	ReactToPost(ctx context.Context, slug, emoji, ip, userAgent string) (*model.ReactionSummary, error)
+	ReactToProject(ctx context.Context, id uint, emoji, ip, userAgent string) (*model.ReactionSummary, error)
+}
+
+// NewsletterService defines methods for newsletter subscriptions and digests
+type NewsletterService interface {
+	Subscribe(ctx context.Context, email string) error
+	Confirm(ctx context.Context, token string) (bool, error)
+	Unsubscribe(ctx context.Context, token string) (bool, error)
+	GetSubscribers(ctx context.Context, status string, page, pageSize int) (*paging.Paginator, error)
+	AddSubscriber(ctx context.Context, email string) (*model.Subscriber, error)
+	DeleteSubscriber(ctx context.Context, id uint) error
+	GetIssues(ctx context.Context, page, pageSize int) (*paging.Paginator, error)
+	SendConfirmation(ctx context.Context, subscriberID uint) error
+	TriggerDigest(ctx context.Context) error
+	SendDigest(ctx context.Context) error
+}
+
+// HealthService defines the readiness check and build information
+type HealthService interface {
+	Ready(ctx context.Context) *model.HealthReport
+	SetDraining()
+	Version() model.VersionInfo
+}
+
+// JobQueue stores work for the background workers
+type JobQueue interface {
+	Enqueue(ctx context.Context, kind string, payload interface{}, opts ...job.Option) (*model.Job, error)
+}
+
+// JobService defines methods for inspecting and retrying background jobs
+type JobService interface {
+	GetJobs(ctx context.Context, filter model.JobFilter, page, pageSize int) (*paging.Paginator, error)
+	GetJob(ctx context.Context, id uint) (*model.Job, error)
+	GetStats(ctx context.Context) (map[string]int64, error)
+	RetryJob(ctx context.Context, id uint) (*model.Job, error)
+	DeleteJob(ctx context.Context, id uint) error
+	GetSchedules(ctx context.Context) ([]model.JobSchedule, error)
+}
+
+// EventBus is woken after a write stored domain events in the outbox, so they
+// are dispatched right away; Publish stores events that come without a write
+type EventBus interface {
+	Wake()
+	Publish(ctx context.Context, events ...model.DomainEvent) error
+}
+
+// WebhookService defines methods for outbound webhooks and their delivery queue
+type WebhookService interface {
+	HandleEvent(ctx context.Context, event model.DomainEvent) error
+	CreateWebhook(ctx context.Context, webhook *model.Webhook) error
+	GetWebhookByID(ctx context.Context, id uint) (*model.Webhook, error)
+	GetAllWebhooks(ctx context.Context) ([]model.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *model.Webhook) error
+	DeleteWebhook(ctx context.Context, id uint) error
+	GetDeliveries(ctx context.Context, webhookID uint, status string, page, pageSize int) (*paging.Paginator, error)
+	Redeliver(ctx context.Context, deliveryID uint) (*model.WebhookDelivery, error)
+	DeliverDue(ctx context.Context) error
+	RunDeliveries(ctx context.Context, interval time.Duration)
+}
+
+// SeriesService defines methods for blog post series service
+type SeriesService interface {
+	CreateSeries(ctx context.Context, series *model.Series) error
+	GetSeriesByID(ctx context.Context, id uint) (*model.Series, error)
+	GetSeriesBySlug(ctx context.Context, slug string) (*model.Series, error)
+	GetAllSeries(ctx context.Context) ([]model.Series, error)
+	UpdateSeries(ctx context.Context, series *model.Series) error
+	SetSeriesPosts(ctx context.Context, id uint, postIDs []uint) error
+	DeleteSeries(ctx context.Context, id uint) error
 }
 
 // PortfolioService defines methods for portfolio service
 type PortfolioService interface {
-	CreateProject(project *model.PortfolioProject) error
-	GetProjectByID(id uint) (*model.PortfolioProject, error)
-	GetAllProjects(projectType string, page, pageSize int) (*paging.Paginator, error)
-	UpdateProject(project *model.PortfolioProject) error
-	DeleteProject(id uint) error
-	GetBlogBaseQuery() *gorm.DB
+	CreateProject(ctx context.Context, project *model.PortfolioProject) error
+	GetProjectByID(ctx context.Context, id uint) (*model.PortfolioProject, error)
+	GetAllProjects(ctx context.Context, filter model.ProjectFilter, page, pageSize int) (*paging.Paginator, error)
+	UpdateProject(ctx context.Context, project *model.PortfolioProject) error
+	ReorderProjects(ctx context.Context, ids []uint) error
+	DeleteProject(ctx context.Context, id uint) error
+	GetBlogBaseQuery(ctx context.Context) *gorm.DB
+}
+
+// ProjectImportService defines methods for drafting and syncing projects from git hosts
+type ProjectImportService interface {
+	DraftFromRepository(ctx context.Context, repoURL string) (*model.PortfolioProject, *githost.Repository, error)
+	RefreshRepositoryStats(ctx context.Context) (int, error)
+}
+
+// ProjectTypeService defines methods for project type service
+type ProjectTypeService interface {
+	CreateProjectType(ctx context.Context, projectType *model.ProjectType) error
+	GetProjectTypeByID(ctx context.Context, id uint) (*model.ProjectType, error)
+	GetAllProjectTypes(ctx context.Context) ([]model.ProjectType, error)
+	UpdateProjectType(ctx context.Context, projectType *model.ProjectType) error
+	DeleteProjectType(ctx context.Context, id uint) error
+}
+
+// TechnologyService defines methods for technology catalog service
+type TechnologyService interface {
+	CreateTechnology(ctx context.Context, technology *model.Technology) error
+	GetTechnologyByID(ctx context.Context, id uint) (*model.Technology, error)
+	GetAllTechnologies(ctx context.Context) ([]model.Technology, error)
+	UpdateTechnology(ctx context.Context, technology *model.Technology) error
+	DeleteTechnology(ctx context.Context, id uint) error
 }
 
 // ContactService defines methods for contact service
 type ContactService interface {
-	CreateContact(submission *model.ContactSubmission) error
-	GetAllContacts(page, pageSize int) (*paging.Paginator, error)
-	MarkContactAsRead(id uint) error
-	DeleteContact(id uint) error
-	GetBlogBaseQuery() *gorm.DB
+	CreateContact(ctx context.Context, submission *model.ContactSubmission) error
+	GetAllContacts(ctx context.Context, page, pageSize int) (*paging.Paginator, error)
+	MarkContactAsRead(ctx context.Context, id uint) error
+	DeleteContact(ctx context.Context, id uint) error
+	GetBlogBaseQuery(ctx context.Context) *gorm.DB
 }
 
 // AuthService defines methods for auth service
 type AuthService interface {
-	Register(username, email, password string) (*model.User, error)
-	Login(email, password string) (*model.AuthResponse, error)
-	RefreshToken(refreshToken string) (*model.AuthResponse, error)
+	Register(ctx context.Context, username, email, password string) (*model.User, error)
+	Login(ctx context.Context, email, password string) (*model.AuthResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error)
 	ValidateToken(tokenString string) (jwt.MapClaims, error)
 }
+
+// UserService defines methods for managing user accounts
+type UserService interface {
+	CreateUser(ctx context.Context, username, email, password, role string) (*model.User, error)
+	GetUser(ctx context.Context, identifier string) (*model.User, error)
+	SetRole(ctx context.Context, identifier, role string) (*model.User, error)
+	SetActive(ctx context.Context, identifier string, active bool) (*model.User, error)
+	ResetPassword(ctx context.Context, identifier, password string) (*model.User, error)
+	CountAdmins(ctx context.Context) (int64, error)
+}
+
+// ArchiveService defines methods for exporting and importing all content
+type ArchiveService interface {
+	Export(ctx context.Context, w io.Writer, opts model.ExportOptions) error
+	Import(ctx context.Context, r io.ReaderAt, size int64, dryRun bool) (*model.ImportReport, error)
+}
+
+// MarkdownImportService defines methods for importing blog posts from Markdown files
+type MarkdownImportService interface {
+	Import(ctx context.Context, files []model.MarkdownFile, dryRun bool) (*model.MarkdownImportReport, error)
+}
+
+// StaticExportService defines methods for rendering the public API into static files
+type StaticExportService interface {
+	Generate(ctx context.Context, full bool) (*model.StaticExportReport, error)
+}
+
+// SitemapService defines methods for the sitemap and robots.txt served to crawlers
+type SitemapService interface {
+	Index(ctx context.Context) (*model.SitemapIndex, error)
+	Sitemap(ctx context.Context, kind string, page int) (*model.SitemapURLSet, error)
+	RobotsTxt() string
+}
+
+// SocialCardService defines methods for the Open Graph images of posts and projects
+type SocialCardService interface {
+	PostCard(ctx context.Context, slug string) (*model.SocialCard, error)
+	ProjectCard(ctx context.Context, id uint) (*model.SocialCard, error)
+}
+
+// AnalyticsService defines methods for cookieless page view analytics
+type AnalyticsService interface {
+	RecordView(ctx context.Context, req model.PageViewRequest, ip, userAgent string) error
+	TopContent(ctx context.Context, q model.AnalyticsQuery) ([]model.ContentStat, error)
+	TimeSeries(ctx context.Context, q model.AnalyticsQuery) ([]model.AnalyticsPoint, error)
+	Referrers(ctx context.Context, q model.AnalyticsQuery) ([]model.ReferrerStat, error)
+	Rollup(ctx context.Context) error
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+// sitemapMaxURLs is the limit the sitemap protocol puts on a single file
+const sitemapMaxURLs = 50000
+
+var sitemapKinds = []string{model.SitemapPosts, model.SitemapTags, model.SitemapProjects}
+
+type sitemapService struct {
+	repo repository.SitemapRepository
+	site config.SiteConfig
+}
+
+func NewSitemapService(repo repository.SitemapRepository, site config.SiteConfig) SitemapService {
+	return &sitemapService{repo: repo, site: site}
+}
+
+// Index lists one sitemap per content type, split into pages when a type
+// outgrows the per-file limit. Empty types are left out.
+func (s *sitemapService) Index(ctx context.Context) (_ *model.SitemapIndex, err error) {
+	ctx, span := tracer.Start(ctx, "SitemapService.Index")
+	defer endSpan(span, &err)
+
+	index := &model.SitemapIndex{}
+	for _, kind := range sitemapKinds {
+		entries, err := s.entries(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for page := 1; (page-1)*sitemapMaxURLs < len(entries); page++ {
+			chunk := sitemapPage(entries, page)
+			var latest time.Time
+			for _, entry := range chunk {
+				if entry.LastMod.After(latest) {
+					latest = entry.LastMod
+				}
+			}
+			index.Sitemaps = append(index.Sitemaps, model.SitemapIndexed{
+				Loc:     s.sitemapURL(kind, page),
+				LastMod: formatLastMod(latest),
+			})
+		}
+	}
+	return index, nil
+}
+
+// Sitemap returns one page of the sitemap for kind, or nil when there is no such sitemap
+func (s *sitemapService) Sitemap(ctx context.Context, kind string, page int) (_ *model.SitemapURLSet, err error) {
+	ctx, span := tracer.Start(ctx, "SitemapService.Sitemap")
+	defer endSpan(span, &err)
+
+	if !isSitemapKind(kind) || page < 1 {
+		return nil, nil
+	}
+
+	entries, err := s.entries(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	chunk := sitemapPage(entries, page)
+	if chunk == nil && page > 1 {
+		return nil, nil
+	}
+
+	urlSet := &model.SitemapURLSet{URLs: make([]model.SitemapURL, 0, len(chunk))}
+	for _, entry := range chunk {
+		urlSet.URLs = append(urlSet.URLs, model.SitemapURL{
+			Loc:     s.pageURL(kind, entry.Key),
+			LastMod: formatLastMod(entry.LastMod),
+		})
+	}
+	return urlSet, nil
+}
+
+// RobotsTxt returns ROBOTS_TXT verbatim when configured, otherwise rules built
+// from ROBOTS_DISALLOW that point crawlers at the sitemap index
+func (s *sitemapService) RobotsTxt() string {
+	if s.site.RobotsTxt != "" {
+		return strings.TrimRight(s.site.RobotsTxt, "\n") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(s.site.RobotsDisallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range s.site.RobotsDisallow {
+		fmt.Fprintf(&b, "Disallow: %s\n", path)
+	}
+	fmt.Fprintf(&b, "\nSitemap: %s/sitemap.xml\n", s.site.APIURL)
+	return b.String()
+}
+
+func (s *sitemapService) entries(ctx context.Context, kind string) ([]model.SitemapEntry, error) {
+	switch kind {
+	case model.SitemapPosts:
+		return s.repo.Posts(ctx, time.Now())
+	case model.SitemapTags:
+		return s.repo.Tags(ctx, time.Now())
+	default:
+		return s.repo.Projects(ctx)
+	}
+}
+
+func (s *sitemapService) pageURL(kind, key string) string {
+	switch kind {
+	case model.SitemapPosts:
+		return s.site.PostURL(key)
+	case model.SitemapTags:
+		return s.site.TagURL(key)
+	default:
+		return s.site.URL + s.site.PortfolioPath + "/" + key
+	}
+}
+
+func (s *sitemapService) sitemapURL(kind string, page int) string {
+	loc := fmt.Sprintf("%s/sitemaps/%s.xml", s.site.APIURL, kind)
+	if page > 1 {
+		loc += fmt.Sprintf("?page=%d", page)
+	}
+	return loc
+}
+
+func sitemapPage(entries []model.SitemapEntry, page int) []model.SitemapEntry {
+	start := (page - 1) * sitemapMaxURLs
+	if start >= len(entries) {
+		return nil
+	}
+	end := start + sitemapMaxURLs
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[start:end]
+}
+
+func isSitemapKind(kind string) bool {
+	for _, k := range sitemapKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/socialcard"
+)
+
+// socialCardCacheSize bounds how many rendered cards are kept in memory
+const socialCardCacheSize = 256
+
+type cachedCard struct {
+	key string
+	png []byte
+}
+
+type socialCardService struct {
+	blogRepo         repository.BlogRepository
+	portfolioService PortfolioService
+	site             config.SiteConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+func NewSocialCardService(blogRepo repository.BlogRepository, portfolioService PortfolioService, site config.SiteConfig) SocialCardService {
+	return &socialCardService{
+		blogRepo:         blogRepo,
+		portfolioService: portfolioService,
+		site:             site,
+		entries:          make(map[string]*list.Element),
+		order:            list.New(),
+	}
+}
+
+// PostCard returns the card of a visible post, or nil for drafts and scheduled posts
+func (s *socialCardService) PostCard(ctx context.Context, slug string) (_ *model.SocialCard, err error) {
+	ctx, span := tracer.Start(ctx, "SocialCardService.PostCard")
+	defer endSpan(span, &err)
+
+	// Straight from the repository: the card needs no series navigation or related posts
+	post, err := s.blogRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || !post.Published || post.PublishAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = tag.Name
+	}
+	return s.card(post.SEO, post.Title, tags)
+}
+
+func (s *socialCardService) ProjectCard(ctx context.Context, id uint) (_ *model.SocialCard, err error) {
+	ctx, span := tracer.Start(ctx, "SocialCardService.ProjectCard")
+	defer endSpan(span, &err)
+
+	project, err := s.portfolioService.GetProjectByID(ctx, id)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	return s.card(project.SEO, project.Title, project.Technologies)
+}
+
+func (s *socialCardService) card(seo model.SEO, title string, tags []string) (*model.SocialCard, error) {
+	if seo.OGImageURL != "" {
+		return &model.SocialCard{RedirectURL: seo.OGImageURL}, nil
+	}
+	if seo.MetaTitle != "" {
+		title = seo.MetaTitle
+	}
+
+	card := socialcard.Card{Title: title, Tags: tags, SiteName: s.site.Title}
+	key := socialCardKey(card)
+	if png := s.cached(key); png != nil {
+		return &model.SocialCard{PNG: png, ETag: key}, nil
+	}
+
+	png, err := socialcard.Render(card)
+	if err != nil {
+		return nil, err
+	}
+	s.store(key, png)
+	return &model.SocialCard{PNG: png, ETag: key}, nil
+}
+
+// socialCardKey hashes everything drawn on the card, so edits produce a new
+// entry instead of requiring invalidation
+func socialCardKey(card socialcard.Card) string {
+	sum := sha256.Sum256([]byte(card.Title + "\x00" + strings.Join(card.Tags, "\x00") + "\x00" + card.SiteName))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (s *socialCardService) cached(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*cachedCard).png
+}
+
+func (s *socialCardService) store(key string, png []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&cachedCard{key: key, png: png})
+
+	for s.order.Len() > socialCardCacheSize {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*cachedCard).key)
+	}
+}
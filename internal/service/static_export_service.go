@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+)
+
+const (
+	// staticPageSize matches the default page_size of the list endpoints
+	staticPageSize = 10
+	staticFeedSize = 20
+
+	// staticManifestFile records the hash of every generated file for incremental runs
+	staticManifestFile = ".manifest.json"
+)
+
+type staticManifest struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Files       map[string]string `json:"files"` // Path relative to the export directory -> sha256
+}
+
+type staticExportService struct {
+	blogService        BlogService
+	seriesService      SeriesService
+	portfolioService   PortfolioService
+	projectTypeService ProjectTypeService
+	technologyService  TechnologyService
+	site               config.SiteConfig
+	mu                 sync.Mutex
+}
+
+func NewStaticExportService(
+	blogService BlogService,
+	seriesService SeriesService,
+	portfolioService PortfolioService,
+	projectTypeService ProjectTypeService,
+	technologyService TechnologyService,
+	site config.SiteConfig,
+) StaticExportService {
+	return &staticExportService{
+		blogService:        blogService,
+		seriesService:      seriesService,
+		portfolioService:   portfolioService,
+		projectTypeService: projectTypeService,
+		technologyService:  technologyService,
+		site:               site,
+	}
+}
+
+// Generate renders the public API responses into site.StaticExportDir, laid out so that
+// /api/blogs/hello is served from api/blogs/hello/index.json. Unless full is set only files
+// whose content changed since the last run are rewritten, and files for content that no
+// longer exists are removed.
+func (s *staticExportService) Generate(ctx context.Context, full bool) (_ *model.StaticExportReport, err error) {
+	ctx, span := tracer.Start(ctx, "StaticExportService.Generate")
+	defer endSpan(span, &err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.site.StaticExportDir
+	if dir == "" {
+		return nil, newValidationError("static export directory is not configured")
+	}
+
+	files, err := s.render(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.readManifest(ctx, dir)
+	manifest := staticManifest{GeneratedAt: time.Now().UTC(), Files: make(map[string]string, len(files))}
+	report := &model.StaticExportReport{Dir: dir, GeneratedAt: manifest.GeneratedAt, Written: []string{}, Removed: []string{}}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		sum := sha256.Sum256(files[p])
+		hash := hex.EncodeToString(sum[:])
+		manifest.Files[p] = hash
+
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if !full && previous.Files[p] == hash {
+			if _, err := os.Stat(target); err == nil {
+				report.Unchanged++
+				continue
+			}
+		}
+
+		if err := writeFileAtomic(target, files[p]); err != nil {
+			return nil, err
+		}
+		report.Written = append(report.Written, p)
+	}
+
+	for p := range previous.Files {
+		if _, ok := files[p]; ok {
+			continue
+		}
+		if err := removeStaticFile(dir, p); err != nil {
+			return nil, err
+		}
+		report.Removed = append(report.Removed, p)
+	}
+	sort.Strings(report.Removed)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, staticManifestFile), data); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// render builds every exported file in memory, keyed by its slash-separated path
+func (s *staticExportService) render(ctx context.Context) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	add := func(p string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		files[p] = data
+		return nil
+	}
+
+	// Blog list pages and the posts on them
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		paginator, err := s.blogService.GetPublishedBlogs(ctx, page, staticPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(staticListPath("api/blogs", page), paginator); err != nil {
+			return nil, err
+		}
+
+		posts, ok := paginator.Records.(*[]model.BlogPost)
+		if !ok {
+			return nil, errors.New("unexpected blog list records")
+		}
+		for _, listed := range *posts {
+			if !isSafePathSegment(listed.Slug) {
+				slog.WarnContext(ctx, "static export: skipping post with unusable slug", "post_id", listed.ID, "slug", listed.Slug)
+				continue
+			}
+
+			// Fetched again for the series navigation the list leaves out
+			post, err := s.blogService.GetBlogBySlug(ctx, listed.Slug)
+			if err != nil {
+				return nil, err
+			}
+			if post == nil {
+				continue
+			}
+			if err := add(path.Join("api/blogs", post.Slug, "index.json"), post); err != nil {
+				return nil, err
+			}
+		}
+
+		if page >= paginator.TotalPage {
+			break
+		}
+	}
+
+	feed, err := s.buildFeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := add("api/blogs/feed.json", feed); err != nil {
+		return nil, err
+	}
+
+	allSeries, err := s.seriesService.GetAllSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := add("api/series/index.json", allSeries); err != nil {
+		return nil, err
+	}
+	for _, listed := range allSeries {
+		if !isSafePathSegment(listed.Slug) {
+			continue
+		}
+		series, err := s.seriesService.GetSeriesBySlug(ctx, listed.Slug)
+		if err != nil {
+			return nil, err
+		}
+		if series == nil {
+			continue
+		}
+		if err := add(path.Join("api/series", series.Slug, "index.json"), series); err != nil {
+			return nil, err
+		}
+	}
+
+	// Portfolio list pages and the projects on them
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		paginator, err := s.portfolioService.GetAllProjects(ctx, model.ProjectFilter{}, page, staticPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(staticListPath("api/portfolio", page), paginator); err != nil {
+			return nil, err
+		}
+
+		projects, ok := paginator.Records.(*[]model.PortfolioProject)
+		if !ok {
+			return nil, errors.New("unexpected project list records")
+		}
+		for _, listed := range *projects {
+			project, err := s.portfolioService.GetProjectByID(ctx, listed.ID)
+			if err != nil {
+				return nil, err
+			}
+			if project == nil {
+				continue
+			}
+			if err := add(path.Join("api/portfolio", strconv.FormatUint(uint64(project.ID), 10), "index.json"), project); err != nil {
+				return nil, err
+			}
+		}
+
+		if page >= paginator.TotalPage {
+			break
+		}
+	}
+
+	projectTypes, err := s.projectTypeService.GetAllProjectTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := add("api/portfolio/types/index.json", projectTypes); err != nil {
+		return nil, err
+	}
+
+	technologies, err := s.technologyService.GetAllTechnologies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := add("api/technologies/index.json", technologies); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (s *staticExportService) buildFeed(ctx context.Context) (*model.JSONFeed, error) {
+	paginator, err := s.blogService.GetPublishedBlogs(ctx, 1, staticFeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &model.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       s.site.Title,
+		HomePageURL: s.site.URL,
+		FeedURL:     s.site.URL + "/api/blogs/feed.json",
+		Items:       []model.JSONFeedItem{},
+	}
+
+	posts, ok := paginator.Records.(*[]model.BlogPost)
+	if !ok {
+		return nil, errors.New("unexpected blog list records")
+	}
+	for _, post := range *posts {
+		item := model.JSONFeedItem{
+			ID:            s.site.PostURL(post.Slug),
+			URL:           s.site.PostURL(post.Slug),
+			Title:         post.Title,
+			ContentText:   post.Content,
+			Summary:       post.Summary,
+			Image:         post.ImageURL,
+			DatePublished: post.PublishAt,
+			DateModified:  post.UpdatedAt,
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
+
+func (s *staticExportService) readManifest(ctx context.Context, dir string) staticManifest {
+	var manifest staticManifest
+	data, err := os.ReadFile(filepath.Join(dir, staticManifestFile))
+	if err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			slog.WarnContext(ctx, "static export: ignoring unreadable manifest", "error", err)
+		}
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]string)
+	}
+	return manifest
+}
+
+// staticListPath maps page 1 onto the list path itself and later pages onto base/page/N
+func staticListPath(base string, page int) string {
+	if page == 1 {
+		return path.Join(base, "index.json")
+	}
+	return path.Join(base, "page", strconv.Itoa(page), "index.json")
+}
+
+func isSafePathSegment(segment string) bool {
+	return segment != "" && segment != "." && segment != ".." && !strings.ContainsAny(segment, `/\`)
+}
+
+// writeFileAtomic replaces target in one step so a CDN sync never sees a partial file
+func writeFileAtomic(target string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// removeStaticFile deletes a generated file and the directories it leaves empty.
+// The path comes from the manifest on disk, so one that leads outside dir is refused.
+func removeStaticFile(dir, p string) error {
+	if !filepath.IsLocal(filepath.FromSlash(p)) {
+		return fmt.Errorf("manifest path %q is outside %s", p, dir)
+	}
+	p = path.Clean(p)
+
+	target := filepath.Join(dir, filepath.FromSlash(p))
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for parent := path.Dir(p); parent != "."; parent = path.Dir(parent) {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(parent))); err != nil {
+			break // Not empty
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+)
+
+type technologyService struct {
+	repo repository.TechnologyRepository
+}
+
+func NewTechnologyService(repo repository.TechnologyRepository) TechnologyService {
+	return &technologyService{repo: repo}
+}
+
+func (s *technologyService) CreateTechnology(ctx context.Context, technology *model.Technology) (err error) {
+	ctx, span := tracer.Start(ctx, "TechnologyService.CreateTechnology")
+	defer endSpan(span, &err)
+
+	if err := s.validateTechnology(ctx, technology); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, technology)
+}
+
+func (s *technologyService) GetTechnologyByID(ctx context.Context, id uint) (_ *model.Technology, err error) {
+	ctx, span := tracer.Start(ctx, "TechnologyService.GetTechnologyByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *technologyService) GetAllTechnologies(ctx context.Context) (_ []model.Technology, err error) {
+	ctx, span := tracer.Start(ctx, "TechnologyService.GetAllTechnologies")
+	defer endSpan(span, &err)
+
+	return s.repo.GetAllWithCounts(ctx)
+}
+
+func (s *technologyService) UpdateTechnology(ctx context.Context, technology *model.Technology) (err error) {
+	ctx, span := tracer.Start(ctx, "TechnologyService.UpdateTechnology")
+	defer endSpan(span, &err)
+
+	existing, err := s.repo.GetByID(ctx, technology.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return newValidationError("technology not found")
+	}
+
+	if err := s.validateTechnology(ctx, technology); err != nil {
+		return err
+	}
+
+	technology.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, technology)
+}
+
+func (s *technologyService) DeleteTechnology(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "TechnologyService.DeleteTechnology")
+	defer endSpan(span, &err)
+
+	return s.repo.Delete(ctx, id)
+}
+
+// validateTechnology makes sure neither the name nor any alias already points at another technology
+func (s *technologyService) validateTechnology(ctx context.Context, technology *model.Technology) error {
+	technology.Name = strings.TrimSpace(technology.Name)
+	if technology.Name == "" {
+		return newValidationError("name is required")
+	}
+
+	for _, name := range append([]string{technology.Name}, technology.Aliases...) {
+		existing, err := s.repo.FindByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != technology.ID {
+			return newValidationError(fmt.Sprintf("%q already refers to %q", name, existing.Name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 6
+
+type userService struct {
+	repo repository.UserRepository
+}
+
+func NewUserService(repo repository.UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+func (s *userService) CreateUser(ctx context.Context, username, email, password, role string) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.CreateUser")
+	defer endSpan(span, &err)
+
+	if username == "" || email == "" {
+		return nil, newValidationError("username and email are required")
+	}
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, newValidationError("username already exists")
+	}
+
+	existing, err = s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, newValidationError("email already exists")
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Username:     username,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		Active:       true,
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUser finds a user by username or email
+func (s *userService) GetUser(ctx context.Context, identifier string) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUser")
+	defer endSpan(span, &err)
+
+	user, err := s.repo.GetByUsername(ctx, identifier)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return s.repo.GetByEmail(ctx, identifier)
+}
+
+func (s *userService) SetRole(ctx context.Context, identifier, role string) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetRole")
+	defer endSpan(span, &err)
+
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+	return s.update(ctx, identifier, func(user *model.User) error {
+		user.Role = role
+		return nil
+	})
+}
+
+func (s *userService) SetActive(ctx context.Context, identifier string, active bool) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetActive")
+	defer endSpan(span, &err)
+
+	return s.update(ctx, identifier, func(user *model.User) error {
+		user.Active = active
+		return nil
+	})
+}
+
+func (s *userService) ResetPassword(ctx context.Context, identifier, password string) (_ *model.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.ResetPassword")
+	defer endSpan(span, &err)
+
+	return s.update(ctx, identifier, func(user *model.User) error {
+		passwordHash, err := hashPassword(password)
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = passwordHash
+		return nil
+	})
+}
+
+func (s *userService) CountAdmins(ctx context.Context) (_ int64, err error) {
+	ctx, span := tracer.Start(ctx, "UserService.CountAdmins")
+	defer endSpan(span, &err)
+
+	return s.repo.CountByRole(ctx, model.RoleAdmin)
+}
+
+func (s *userService) update(ctx context.Context, identifier string, change func(user *model.User) error) (*model.User, error) {
+	user, err := s.GetUser(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, newValidationError(fmt.Sprintf("user %q not found", identifier))
+	}
+
+	if err := change(user); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func validateRole(role string) error {
+	if role != model.RoleAdmin && role != model.RoleUser {
+		return newValidationError(fmt.Sprintf("unknown role %q", role))
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", newValidationError(fmt.Sprintf("password must be at least %d characters", minPasswordLength))
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
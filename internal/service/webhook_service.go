@@ -0,0 +1,334 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/pkg/backoff"
+	"github.com/lutestringamend/perwebbe/pkg/paging"
+)
+
+const (
+	webhookBatchSize       = 20
+	webhookSecretBytes     = 32
+	webhookMaxResponseBody = 2048
+	webhookUserAgent       = "perweb-webhooks/1.0"
+)
+
+type webhookService struct {
+	repo   repository.WebhookRepository
+	cfg    config.WebhookConfig
+	client *http.Client
+}
+
+func NewWebhookService(repo repository.WebhookRepository, cfg config.WebhookConfig) WebhookService {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &webhookService{repo: repo, cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, webhook *model.Webhook) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhook")
+	defer endSpan(span, &err)
+
+	if err := s.validateWebhook(webhook); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, webhook)
+}
+
+func (s *webhookService) GetWebhookByID(ctx context.Context, id uint) (_ *model.Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetWebhookByID")
+	defer endSpan(span, &err)
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *webhookService) GetAllWebhooks(ctx context.Context) (_ []model.Webhook, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetAllWebhooks")
+	defer endSpan(span, &err)
+
+	return s.repo.GetAll(ctx)
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, webhook *model.Webhook) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.UpdateWebhook")
+	defer endSpan(span, &err)
+
+	if err := s.validateWebhook(webhook); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, webhook)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteWebhook")
+	defer endSpan(span, &err)
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookID uint, status string, page, pageSize int) (_ *paging.Paginator, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetDeliveries")
+	defer endSpan(span, &err)
+
+	switch status {
+	case "", model.WebhookDeliveryPending, model.WebhookDeliverySucceeded, model.WebhookDeliveryFailed:
+	default:
+		return nil, newValidationError("status must be pending, succeeded or failed")
+	}
+	return s.repo.GetDeliveries(ctx, webhookID, status, page, pageSize)
+}
+
+// Redeliver queues a copy of a delivery, keeping the original in the log.
+// Returns nil when there is no such delivery.
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID uint) (_ *model.WebhookDelivery, err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Redeliver")
+	defer endSpan(span, &err)
+
+	original, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil || original == nil {
+		return nil, err
+	}
+
+	delivery := model.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+		RedeliveryOf:  &original.ID,
+	}
+	deliveries := []model.WebhookDelivery{delivery}
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// HandleEvent queues a delivery of a domain event to every active webhook subscribed to it
+func (s *webhookService) HandleEvent(ctx context.Context, domainEvent model.DomainEvent) (err error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.HandleEvent")
+	defer endSpan(span, &err)
+
+	return s.enqueue(ctx, domainEvent.EventName(), domainEvent)
+}
+
+func (s *webhookService) enqueue(ctx context.Context, event string, data interface{}) error {
+	webhooks, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var subscribed []model.Webhook
+	for _, webhook := range webhooks {
+		if subscribesTo(webhook, event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(model.WebhookPayload{Event: event, CreatedAt: now.UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]model.WebhookDelivery, len(subscribed))
+	for i, webhook := range subscribed {
+		deliveries[i] = model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		}
+	}
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+// RunDeliveries sends due deliveries every interval until ctx is cancelled
+func (s *webhookService) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DeliverDue(ctx); err != nil {
+				slog.ErrorContext(ctx, "webhook delivery failed", "error", err)
+			}
+		}
+	}
+}
+
+// DeliverDue sends every delivery that is due, a batch at a time
+func (s *webhookService) DeliverDue(ctx context.Context) error {
+	// A claimed delivery is retried by another worker if this one dies mid-request
+	lease := s.cfg.Timeout*webhookBatchSize + time.Minute
+
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDue(ctx, time.Now(), lease, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+
+		webhooks := make(map[uint]*model.Webhook)
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				if webhook, err = s.repo.GetByID(ctx, delivery.WebhookID); err != nil {
+					return err
+				}
+				webhooks[delivery.WebhookID] = webhook
+			}
+
+			s.attempt(ctx, webhook, delivery)
+			if err := s.repo.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry with
+// exponential backoff until the attempts are used up
+func (s *webhookService) attempt(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) {
+	now := time.Now()
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus, delivery.ResponseBody, delivery.Error = 0, "", ""
+
+	if webhook == nil || !webhook.Active {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.Error = "webhook is disabled"
+		return
+	}
+
+	delivery.Attempts++
+	err := s.send(ctx, webhook, delivery)
+	delivery.DurationMS = time.Since(now).Milliseconds()
+	if err == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		return
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= s.cfg.MaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = now.Add(backoff.Exponential(s.cfg.BackoffBase, s.cfg.BackoffMax, delivery.Attempts))
+}
+
+func (s *webhookService) send(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(bytes.ToValidUTF8(body, nil))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookService) validateWebhook(webhook *model.Webhook) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" {
+		return newValidationError("name is required")
+	}
+
+	u, err := url.ParseRequestURI(strings.TrimSpace(webhook.URL))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return newValidationError("url must be an http or https URL")
+	}
+	webhook.URL = u.String()
+
+	if len(webhook.Events) == 0 {
+		return newValidationError("events must not be empty")
+	}
+	for _, event := range webhook.Events {
+		if !isWebhookEvent(event) {
+			return newValidationError(fmt.Sprintf("unknown event %q", event))
+		}
+	}
+
+	if webhook.Secret == "" {
+		if webhook.Secret, err = randomToken(webhookSecretBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func subscribesTo(webhook model.Webhook, event string) bool {
+	for _, subscribed := range webhook.Events {
+		if subscribed == event || subscribed == model.WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebhookEvent(event string) bool {
+	if event == model.WebhookEventAll {
+		return true
+	}
+	for _, known := range model.WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "timestamp.payload", which receivers
+// recompute with the shared secret
+func signWebhook(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,64 +1,344 @@
 package main
 
 import (
-	"flag"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/event"
 	"github.com/lutestringamend/perwebbe/internal/handler"
+	"github.com/lutestringamend/perwebbe/internal/job"
+	"github.com/lutestringamend/perwebbe/internal/logging"
+	"github.com/lutestringamend/perwebbe/internal/metrics"
 	"github.com/lutestringamend/perwebbe/internal/middleware"
+	"github.com/lutestringamend/perwebbe/internal/model"
 	"github.com/lutestringamend/perwebbe/internal/repository"
 	"github.com/lutestringamend/perwebbe/internal/service"
+	"github.com/lutestringamend/perwebbe/internal/tracing"
+	"github.com/lutestringamend/perwebbe/pkg/githost"
+	"github.com/lutestringamend/perwebbe/pkg/mailer"
+	"gorm.io/gorm"
 )
 
+const usage = `usage: main <command> [arguments]
+
+commands:
+  serve                     start the HTTP server (default)
+  migrate                   manage database migrations
+  user                      create and manage user accounts
+  seed                      create the first admin and optional demo content
+  export                    write all content to a zip archive
+  import                    load content from a zip archive
+  import-markdown           create or update blog posts from Markdown files
+  static-export             render the public API into static JSON files
+  version                   print build information`
+
 func main() {
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	// Also sends the output of the standard log package through slog
+	slog.SetDefault(logger)
+
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	switch command {
+	case "serve":
+		err = runServe(cfg)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "user":
+		err = runUser(cfg, args)
+	case "seed":
+		err = runSeed(cfg, args)
+	case "export":
+		err = runExport(cfg, args)
+	case "import":
+		err = runImport(cfg, args)
+	case "import-markdown":
+		err = runImportMarkdown(cfg, args)
+	case "static-export":
+		err = runStaticExport(cfg, args)
+	case "version":
+		info := versionInfo()
+		fmt.Printf("version: %s\ncommit: %s\nbuilt: %s\ngo: %s\n", info.Version, info.Commit, info.BuildTime, info.GoVersion)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		slog.Error(command+" failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func runServe(cfg config.Config) error {
+	serverConfig, err := config.LoadServerConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load server config: %w", err)
+	}
+
 	jwtConfig, err := config.LoadJWTConfig()
 	if err != nil {
-		log.Fatalf("failed to load JWT config: %v", err)
+		return fmt.Errorf("failed to load JWT config: %w", err)
+	}
+
+	gitConfig, err := config.LoadGitProviderConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load git provider config: %w", err)
+	}
+
+	siteConfig, err := config.LoadSiteConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load site config: %w", err)
+	}
+
+	analyticsConfig, err := config.LoadAnalyticsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load analytics config: %w", err)
+	}
+
+	commentConfig, err := config.LoadCommentConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load comment config: %w", err)
+	}
+
+	reactionConfig, err := config.LoadReactionConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load reaction config: %w", err)
+	}
+
+	mailConfig, err := config.LoadMailConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load mail config: %w", err)
+	}
+
+	newsletterConfig, err := config.LoadNewsletterConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load newsletter config: %w", err)
+	}
+
+	mailSender, err := newMailer(mailConfig)
+	if err != nil {
+		return err
+	}
+
+	webhookConfig, err := config.LoadWebhookConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load webhook config: %w", err)
+	}
+
+	eventConfig, err := config.LoadEventConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load event config: %w", err)
+	}
+
+	jobConfig, err := config.LoadJobConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load job config: %w", err)
+	}
+
+	metricsConfig, err := config.LoadMetricsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load metrics config: %w", err)
 	}
 
 	db, err := config.SetupDatabase(cfg)
 	if err != nil {
-		log.Fatalf("failed to connect to db: %v", err)
+		return fmt.Errorf("failed to connect to db: %w", err)
 	}
 
-	withMigrations := flag.Bool("with-migrations", false, "with db migrations")
-	flag.Parse()
+	warnPendingMigrations(db)
 
-	if *withMigrations {
-		if err := config.RunMigrations(db); err != nil {
-			log.Fatalf("failed to run db migrations : %v", err)
+	if metricsConfig.Enabled {
+		if err := db.Use(metrics.GormPlugin{DBName: cfg.DBName}); err != nil {
+			return fmt.Errorf("failed to set up database metrics: %w", err)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg, versionInfo().Version)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	if tracing.Enabled(cfg) {
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			return fmt.Errorf("failed to set up database tracing: %w", err)
 		}
 	}
 
 	blogRepo := repository.NewBlogRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
 	portfolioRepo := repository.NewPortfolioRepository(db)
+	projectTypeRepo := repository.NewProjectTypeRepository(db)
+	technologyRepo := repository.NewTechnologyRepository(db)
 	contactRepo := repository.NewContactRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	archiveRepo := repository.NewArchiveRepository(db)
+	sitemapRepo := repository.NewSitemapRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	newsletterRepo := repository.NewNewsletterRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	healthRepo := repository.NewHealthRepository(db)
 
-	blogService := service.NewBlogService(blogRepo)
-	portfolioService := service.NewPortfolioService(portfolioRepo)
-	contactService := service.NewContactService(contactRepo)
+	eventBus := event.NewBus(outboxRepo, eventConfig)
+	jobQueue := job.NewQueue(jobRepo, jobConfig)
+	blogService := service.NewBlogService(blogRepo, seriesRepo, eventBus, jobQueue)
+	seriesService := service.NewSeriesService(seriesRepo)
+	commentService := service.NewCommentService(commentRepo, blogRepo, userRepo, commentConfig)
+	portfolioService := service.NewPortfolioService(portfolioRepo, projectTypeRepo, eventBus)
+	projectTypeService := service.NewProjectTypeService(projectTypeRepo)
+	technologyService := service.NewTechnologyService(technologyRepo)
+	gitRegistry := githost.NewRegistry(
+		githost.NewGitHub(gitConfig.GitHubHost, gitConfig.GitHubAPIURL, gitConfig.GitHubToken, nil),
+		githost.NewGitLab(gitConfig.GitLabHost, gitConfig.GitLabAPIURL, gitConfig.GitLabToken, nil),
+	)
+	projectImportService := service.NewProjectImportService(gitRegistry, portfolioRepo, technologyRepo)
+	contactService := service.NewContactService(contactRepo, eventBus)
 	authService := service.NewAuthService(userRepo, jwtConfig)
+	archiveService := service.NewArchiveService(archiveRepo)
+	markdownImportService := service.NewMarkdownImportService(blogService)
+	sitemapService := service.NewSitemapService(sitemapRepo, siteConfig)
+	socialCardService := service.NewSocialCardService(blogRepo, portfolioService, siteConfig)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, analyticsConfig, siteConfig)
+	reactionService := service.NewReactionService(reactionRepo, blogRepo, portfolioRepo, reactionConfig)
+	newsletterService := service.NewNewsletterService(newsletterRepo, mailSender, jobQueue, newsletterConfig, siteConfig)
+	webhookService := service.NewWebhookService(webhookRepo, webhookConfig)
+	jobService := service.NewJobService(jobRepo)
+	healthService := service.NewHealthService(healthRepo, versionInfo())
+	staticExportService := service.NewStaticExportService(blogService, seriesService, portfolioService, projectTypeService, technologyService, siteConfig)
 
 	blogHandler := handler.NewBlogHandler(blogService)
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+	commentHandler := handler.NewCommentHandler(commentService)
 	portfolioHandler := handler.NewPortfolioHandler(portfolioService)
+	projectTypeHandler := handler.NewProjectTypeHandler(projectTypeService)
+	technologyHandler := handler.NewTechnologyHandler(technologyService)
+	projectImportHandler := handler.NewProjectImportHandler(projectImportService)
 	contactHandler := handler.NewContactHandler(contactService)
 	authHandler := handler.NewAuthHandler(authService)
+	archiveHandler := handler.NewArchiveHandler(archiveService)
+	markdownImportHandler := handler.NewMarkdownImportHandler(markdownImportService)
+	staticExportHandler := handler.NewStaticExportHandler(staticExportService)
+	sitemapHandler := handler.NewSitemapHandler(sitemapService)
+	socialCardHandler := handler.NewSocialCardHandler(socialCardService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	newsletterHandler := handler.NewNewsletterHandler(newsletterService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	jobHandler := handler.NewJobHandler(jobService)
+	healthHandler := handler.NewHealthHandler(healthService)
+	metricsHandler := handler.NewMetricsHandler(metricsConfig.Token)
+
+	// Cancelled on SIGINT or SIGTERM, so the background work can stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Joined on shutdown so no delivery, event or job is cut off mid-write
+	var workers sync.WaitGroup
+	if webhookConfig.PollInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			webhookService.RunDeliveries(ctx, webhookConfig.PollInterval)
+		}()
+	}
+
+	// Side effects of content changes subscribe here rather than being called by the services
+	eventBus.SubscribeAll("webhooks", webhookService.HandleEvent)
+	event.Subscribe(eventBus, "metrics", func(context.Context, event.ContactReceived) error {
+		metrics.ContactsReceived.Inc()
+		return nil
+	})
+	event.Subscribe(eventBus, "metrics", func(context.Context, event.BlogPublished) error {
+		metrics.PostsPublished.Inc()
+		return nil
+	})
+	if eventConfig.PollInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			eventBus.Run(ctx, eventConfig.PollInterval)
+		}()
+	}
+
+	jobQueue.Register(model.JobKindAnalyticsRollup, func(ctx context.Context, _ *model.Job) error {
+		return analyticsService.Rollup(ctx)
+	})
+	job.Handle(jobQueue, model.JobKindBlogPublish, blogService.PublishScheduled)
+	job.Handle(jobQueue, model.JobKindNewsletterConfirmation, newsletterService.SendConfirmation)
+	jobQueue.Register(model.JobKindNewsletterDigest, func(ctx context.Context, _ *model.Job) error {
+		return newsletterService.SendDigest(ctx)
+	})
+	jobQueue.Register(model.JobKindRepositoryRefresh, func(ctx context.Context, _ *model.Job) error {
+		refreshed, err := projectImportService.RefreshRepositoryStats(ctx)
+		slog.InfoContext(ctx, "repository refresh finished", "updated", refreshed)
+		return err
+	})
+
+	rollupInterval := analyticsConfig.RollupInterval
+	if !analyticsConfig.Enabled {
+		rollupInterval = 0
+	}
+	if err := scheduleEvery(ctx, jobQueue, "analytics-rollup", rollupInterval, model.JobKindAnalyticsRollup); err != nil {
+		return err
+	}
+	if err := scheduleEvery(ctx, jobQueue, "newsletter-digest", newsletterConfig.DigestInterval, model.JobKindNewsletterDigest); err != nil {
+		return err
+	}
+	if err := scheduleEvery(ctx, jobQueue, "repository-refresh", gitConfig.RefreshInterval, model.JobKindRepositoryRefresh); err != nil {
+		return err
+	}
+
+	if jobConfig.Workers > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			jobQueue.Run(ctx)
+		}()
+	}
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	if tracing.Enabled(cfg) {
+		router.Use(middleware.TracingMiddleware())
+	}
+	router.Use(middleware.LoggerMiddleware(), middleware.RecoveryMiddleware())
 
-	router := gin.Default()
+	if metricsConfig.Enabled {
+		router.Use(middleware.MetricsMiddleware())
+	}
 
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -69,7 +349,20 @@ func main() {
 		c.Next()
 	})
 
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+	router.GET("/version", healthHandler.Version)
+	if metricsConfig.Enabled {
+		router.GET("/metrics", metricsHandler.Serve)
+	}
+
+	router.GET("/sitemap.xml", sitemapHandler.GetIndex)
+	router.GET("/sitemaps/:file", sitemapHandler.GetSitemap)
+	router.GET("/robots.txt", sitemapHandler.GetRobotsTxt)
+
 	jwtAuth := middleware.JWTAuthMiddleware(jwtConfig)
+	optionalJWTAuth := middleware.OptionalJWTAuthMiddleware(jwtConfig)
+	adminOnly := middleware.RequireRole(model.RoleAdmin)
 
 	api := router.Group("/api")
 	{
@@ -85,20 +378,95 @@ func main() {
 		{
 			blogRoutes.GET("/", blogHandler.GetAllBlogs)
 			blogRoutes.GET("/:slug", blogHandler.GetBlogBySlug)
+			blogRoutes.GET("/:slug/card.png", socialCardHandler.GetPostCard)
+			blogRoutes.GET("/:slug/comments", commentHandler.GetComments)
 			blogRoutes.POST("/", jwtAuth, blogHandler.CreateBlog)
+			blogRoutes.POST("/:slug/comments", optionalJWTAuth, commentHandler.CreateComment)
+			blogRoutes.POST("/:slug/reactions", reactionHandler.ReactToPost)
 			blogRoutes.PUT("/:id", jwtAuth, blogHandler.UpdateBlog)
+			blogRoutes.PUT("/:id/related", jwtAuth, adminOnly, blogHandler.SetRelatedPins)
 			blogRoutes.DELETE("/:id", jwtAuth, blogHandler.DeleteBlog)
 		}
 
+		seriesRoutes := api.Group("/series")
+		{
+			seriesRoutes.GET("/", seriesHandler.GetAllSeries)
+			seriesRoutes.GET("/:slug", seriesHandler.GetSeriesBySlug)
+			seriesRoutes.POST("/", jwtAuth, adminOnly, seriesHandler.CreateSeries)
+			seriesRoutes.PUT("/:id", jwtAuth, adminOnly, seriesHandler.UpdateSeries)
+			seriesRoutes.PUT("/:id/posts", jwtAuth, adminOnly, seriesHandler.SetSeriesPosts)
+			seriesRoutes.DELETE("/:id", jwtAuth, adminOnly, seriesHandler.DeleteSeries)
+		}
+
 		portfolioRoutes := api.Group("/portfolio")
 		{
 			portfolioRoutes.GET("/", portfolioHandler.GetAllProjects)
+			portfolioRoutes.GET("/types", projectTypeHandler.GetAllProjectTypes)
+			portfolioRoutes.POST("/types", jwtAuth, adminOnly, projectTypeHandler.CreateProjectType)
+			portfolioRoutes.PUT("/types/:id", jwtAuth, adminOnly, projectTypeHandler.UpdateProjectType)
+			portfolioRoutes.DELETE("/types/:id", jwtAuth, adminOnly, projectTypeHandler.DeleteProjectType)
 			portfolioRoutes.GET("/:id", portfolioHandler.GetProject)
+			portfolioRoutes.GET("/:id/card.png", socialCardHandler.GetProjectCard)
 			portfolioRoutes.POST("/", jwtAuth, portfolioHandler.CreateProject)
+			portfolioRoutes.POST("/:id/reactions", reactionHandler.ReactToProject)
+			portfolioRoutes.POST("/import", jwtAuth, adminOnly, projectImportHandler.ImportProject)
+			portfolioRoutes.POST("/import/refresh", jwtAuth, adminOnly, projectImportHandler.RefreshRepositories)
+			portfolioRoutes.PUT("/order", jwtAuth, adminOnly, portfolioHandler.ReorderProjects)
 			portfolioRoutes.PUT("/:id", jwtAuth, portfolioHandler.UpdateProject)
 			portfolioRoutes.DELETE("/:id", jwtAuth, portfolioHandler.DeleteProject)
 		}
 
+		technologyRoutes := api.Group("/technologies")
+		{
+			technologyRoutes.GET("/", technologyHandler.GetAllTechnologies)
+			technologyRoutes.POST("/", jwtAuth, adminOnly, technologyHandler.CreateTechnology)
+			technologyRoutes.PUT("/:id", jwtAuth, adminOnly, technologyHandler.UpdateTechnology)
+			technologyRoutes.DELETE("/:id", jwtAuth, adminOnly, technologyHandler.DeleteTechnology)
+		}
+
+		adminRoutes := api.Group("/admin", jwtAuth, adminOnly)
+		{
+			adminRoutes.GET("/export", archiveHandler.Export)
+			adminRoutes.POST("/import", archiveHandler.Import)
+			adminRoutes.POST("/import/markdown", markdownImportHandler.Import)
+			adminRoutes.POST("/static-export", staticExportHandler.Generate)
+			adminRoutes.GET("/analytics/top", analyticsHandler.GetTopContent)
+			adminRoutes.GET("/analytics/timeseries", analyticsHandler.GetTimeSeries)
+			adminRoutes.GET("/analytics/referrers", analyticsHandler.GetReferrers)
+			adminRoutes.POST("/analytics/rollup", analyticsHandler.Rollup)
+			adminRoutes.GET("/comments", commentHandler.GetModerationQueue)
+			adminRoutes.PUT("/comments/moderate", commentHandler.ModerateComments)
+			adminRoutes.DELETE("/comments/:id", commentHandler.DeleteComment)
+			adminRoutes.GET("/newsletter/subscribers", newsletterHandler.GetSubscribers)
+			adminRoutes.POST("/newsletter/subscribers", newsletterHandler.AddSubscriber)
+			adminRoutes.DELETE("/newsletter/subscribers/:id", newsletterHandler.DeleteSubscriber)
+			adminRoutes.GET("/newsletter/issues", newsletterHandler.GetIssues)
+			adminRoutes.POST("/newsletter/digest", newsletterHandler.SendDigest)
+			adminRoutes.GET("/webhooks", webhookHandler.GetAllWebhooks)
+			adminRoutes.POST("/webhooks", webhookHandler.CreateWebhook)
+			adminRoutes.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			adminRoutes.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			adminRoutes.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries)
+			adminRoutes.POST("/webhooks/deliveries/:id/redeliver", webhookHandler.Redeliver)
+			adminRoutes.GET("/jobs", jobHandler.GetJobs)
+			adminRoutes.GET("/jobs/stats", jobHandler.GetStats)
+			adminRoutes.GET("/jobs/schedules", jobHandler.GetSchedules)
+			adminRoutes.GET("/jobs/:id", jobHandler.GetJob)
+			adminRoutes.POST("/jobs/:id/retry", jobHandler.RetryJob)
+			adminRoutes.DELETE("/jobs/:id", jobHandler.DeleteJob)
+		}
+
+		api.POST("/analytics/views", analyticsHandler.RecordView)
+		api.GET("/reactions", reactionHandler.GetEmojis)
+
+		newsletterRoutes := api.Group("/newsletter")
+		{
+			newsletterRoutes.POST("/subscribe", newsletterHandler.Subscribe)
+			newsletterRoutes.GET("/confirm", newsletterHandler.Confirm)
+			newsletterRoutes.GET("/unsubscribe", newsletterHandler.ConfirmUnsubscribe)
+			newsletterRoutes.POST("/unsubscribe", newsletterHandler.Unsubscribe)
+		}
+
 		contactRoutes := api.Group("/contacts")
 		{
 			contactRoutes.POST("/", contactHandler.CreateContact)
@@ -108,10 +476,100 @@ func main() {
 		}
 	}
 
-	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("server starting on %s", serverAddr)
-	if err := router.Run(serverAddr); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.ServerPort),
+		Handler:           router,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+		ReadTimeout:       serverConfig.ReadTimeout,
+		WriteTimeout:      serverConfig.WriteTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
 	}
 
+	slog.Info("server starting", "addr", server.Addr)
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case err := <-serverErr:
+		runErr = fmt.Errorf("failed to start server: %w", err)
+	case <-ctx.Done():
+		// A second signal kills the process right away
+		stop()
+		runErr = shutdown(server, healthService, serverConfig)
+	}
+
+	stop()
+	workers.Wait()
+
+	// Sends the spans still buffered, including those of the shutdown
+	flushCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	return runErr
+}
+
+// shutdown fails the readiness probe for ShutdownDelay, so load balancers
+// stop sending requests, then waits up to ShutdownTimeout for the requests in
+// flight before closing the remaining connections
+func shutdown(server *http.Server, health service.HealthService, cfg config.ServerConfig) error {
+	slog.Info("shutting down")
+	health.SetDraining()
+	time.Sleep(cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+		return fmt.Errorf("failed to drain requests: %w", err)
+	}
+	return nil
+}
+
+// newMailer builds the mailer selected by MAIL_DRIVER
+func newMailer(cfg config.MailConfig) (mailer.Mailer, error) {
+	switch cfg.Driver {
+	case "log", "":
+		return mailer.NewLogMailer(), nil
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mail driver")
+		}
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", cfg.Driver)
+	}
+}
+
+// newEventBus gives CLI commands a bus without subscribers: their events stay
+// in the outbox until the server dispatches them
+func newEventBus(db *gorm.DB) *event.Bus {
+	return event.NewBus(repository.NewOutboxRepository(db), config.EventConfig{})
+}
+
+// newJobQueue gives CLI commands a queue without workers: their jobs wait in
+// the table until a server picks them up
+func newJobQueue(db *gorm.DB) (*job.Queue, error) {
+	jobConfig, err := config.LoadJobConfig()
+	if err != nil {
+		return nil, err
+	}
+	return job.NewQueue(repository.NewJobRepository(db), jobConfig), nil
+}
+
+// scheduleEvery runs a recurring job every interval; 0 leaves it off in this instance
+func scheduleEvery(ctx context.Context, queue *job.Queue, name string, interval time.Duration, kind string) error {
+	if interval <= 0 {
+		return nil
+	}
+	if err := queue.Schedule(ctx, name, "@every "+interval.String(), kind, nil); err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", name, err)
+	}
+	return nil
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+// runImportMarkdown implements the import-markdown subcommand, the CLI twin of
+// POST /api/admin/import/markdown
+func runImportMarkdown(cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("import-markdown", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import-markdown [--dry-run] <directory|archive.zip>")
+	}
+
+	files, err := readMarkdownSource(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	bus := newEventBus(db)
+	jobs, err := newJobQueue(db)
+	if err != nil {
+		return err
+	}
+	importer := service.NewMarkdownImportService(service.NewBlogService(repository.NewBlogRepository(db), repository.NewSeriesRepository(db), bus, jobs))
+
+	report, err := importer.Import(context.Background(), files, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func readMarkdownSource(source string) ([]model.MarkdownFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return service.ReadMarkdownDir(source)
+	}
+
+	if !strings.EqualFold(filepath.Ext(source), ".zip") {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		return []model.MarkdownFile{{Name: info.Name(), Content: content}}, nil
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return service.ReadMarkdownZip(file, info.Size())
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/migration"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status | create [-dir path] <name>"
+
+// runMigrate implements the migrate subcommand
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", "internal/migration/migrations", "directory holding the migration sources")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf(migrateUsage)
+		}
+
+		files, err := migration.Create(*dir, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Println("created", file)
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "up", "down", "status":
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+
+	migrator, err := newMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	}
+
+	return fmt.Errorf(migrateUsage)
+}
+
+func newMigrator(cfg config.Config) (*migration.Migrator, error) {
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migration.NewMigrator(sqlDB)
+}
+
+// warnPendingMigrations logs when the schema is behind the binary; the server
+// no longer migrates on its own, that is what `migrate up` is for
+func warnPendingMigrations(db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return
+	}
+	migrator, err := migration.NewMigrator(sqlDB)
+	if err != nil {
+		slog.Error("failed to load migrations", "error", err)
+		return
+	}
+
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		slog.Error("failed to check migrations", "error", err)
+		return
+	}
+	if pending > 0 {
+		slog.Warn("pending db migrations, run `migrate up`", "pending", pending)
+	}
+}
@@ -0,0 +1,17 @@
+package backoff
+
+import "time"
+
+// Exponential is the delay before the retry that follows the given number of
+// failed attempts: base after the first failure, doubled after each one after
+// that, and never more than max
+func Exponential(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
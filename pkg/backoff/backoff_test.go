@@ -0,0 +1,27 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponential(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second * 30},
+		{1, time.Second * 30},
+		{2, time.Minute},
+		{3, time.Minute * 2},
+		{5, time.Minute * 8},
+		{6, time.Minute * 10},
+		{100, time.Minute * 10},
+	}
+
+	for _, tt := range tests {
+		if got := Exponential(time.Second*30, time.Minute*10, tt.attempts); got != tt.want {
+			t.Errorf("Exponential after %d attempts = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+var delimiter = []byte("---")
+
+// ErrUnterminated is returned when the opening delimiter has no closing one
+var ErrUnterminated = errors.New("front matter is not terminated")
+
+// Split separates YAML front matter enclosed in "---" lines from the body.
+// Documents without front matter are returned whole as the body.
+func Split(data []byte) (frontMatter, body []byte, err error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	if !bytes.HasPrefix(data, append(delimiter, '\n')) {
+		return nil, data, nil
+	}
+
+	rest := data[len(delimiter)+1:]
+	for offset := 0; offset <= len(rest); {
+		end := bytes.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+
+		if bytes.Equal(bytes.TrimRight(line, " \t"), delimiter) {
+			frontMatter = rest[:offset]
+			if end < 0 {
+				return frontMatter, nil, nil
+			}
+			return frontMatter, bytes.TrimLeft(rest[offset+end+1:], "\n"), nil
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+
+	return nil, nil, ErrUnterminated
+}
+
+// Unmarshal decodes the front matter of data into v and returns the body
+func Unmarshal(data []byte, v interface{}) ([]byte, error) {
+	frontMatter, body, err := Split(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(frontMatter) > 0 {
+		if err := yaml.Unmarshal(frontMatter, v); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// StringList accepts either a YAML sequence or a comma separated string
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, item := range bytes.Split([]byte(node.Value), []byte(",")) {
+			if item = bytes.TrimSpace(item); len(item) > 0 {
+				*l = append(*l, string(item))
+			}
+		}
+		return nil
+	}
+
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
@@ -0,0 +1,145 @@
+package githost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupportedHost is returned when no provider handles the repository URL
+	ErrUnsupportedHost = errors.New("unsupported repository host")
+	// ErrNotFound is returned when the provider does not know the repository
+	ErrNotFound = errors.New("repository not found")
+	// errEmpty is returned by GitHub when asking for the commits of an empty repository
+	errEmpty = errors.New("repository is empty")
+)
+
+// Repository is the metadata a provider reports for a repository
+type Repository struct {
+	Provider     string    `json:"provider"`
+	FullName     string    `json:"full_name"`
+	Description  string    `json:"description"`
+	WebURL       string    `json:"web_url"`
+	Homepage     string    `json:"homepage"`
+	Languages    []string  `json:"languages"` // Most used first
+	Topics       []string  `json:"topics"`
+	Stars        int       `json:"stars"`
+	LastCommitAt time.Time `json:"last_commit_at"`
+}
+
+// Provider fetches repository metadata from one git hosting service
+type Provider interface {
+	Name() string
+	Matches(host string) bool
+	Fetch(ctx context.Context, path string) (*Repository, error)
+}
+
+// Registry picks the provider responsible for a repository URL
+type Registry struct {
+	providers []Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	return &Registry{providers: providers}
+}
+
+// Fetch resolves rawURL to a provider and returns the repository metadata
+func (r *Registry) Fetch(ctx context.Context, rawURL string) (*Repository, error) {
+	host, path, err := ParseRepoURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, provider := range r.providers {
+		if provider.Matches(host) {
+			return provider.Fetch(ctx, path)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedHost, host)
+}
+
+// Supports reports whether rawURL is a repository URL on a host some provider handles
+func (r *Registry) Supports(rawURL string) bool {
+	host, _, err := ParseRepoURL(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, provider := range r.providers {
+		if provider.Matches(host) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRepoURL splits a repository URL such as https://github.com/owner/repo.git
+// into its host and repository path
+func ParseRepoURL(rawURL string) (host, path string, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid repository URL %q", rawURL)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if strings.Count(path, "/") < 1 {
+		return "", "", fmt.Errorf("repository URL %q must include owner and name", rawURL)
+	}
+	return strings.ToLower(u.Hostname()), path, nil
+}
+
+// client is the small JSON-over-HTTP helper shared by the providers
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+	authorize  func(req *http.Request)
+}
+
+func (c *client) getJSON(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return errEmpty
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// ignoreEmpty treats a missing commit history as "no last commit" rather than a failure
+func ignoreEmpty(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, errEmpty) {
+		return nil
+	}
+	return err
+}
+
+func httpClientOrDefault(httpClient *http.Client) *http.Client {
+	if httpClient != nil {
+		return httpClient
+	}
+	return &http.Client{Timeout: 15 * time.Second}
+}
@@ -0,0 +1,28 @@
+package githost
+
+import "testing"
+
+func TestRegistrySupports(t *testing.T) {
+	registry := NewRegistry(
+		NewGitHub("github.com", "https://api.github.com", "", nil),
+		NewGitLab("gitlab.com", "https://gitlab.com/api/v4", "", nil),
+	)
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://github.com/owner/repo", true},
+		{"https://GitHub.com/owner/repo.git", true},
+		{"https://gitlab.com/group/sub/project/-/tree/main", true},
+		{"https://bitbucket.org/owner/repo", false},
+		{"https://github.com/owner", false},
+		{"not a url", false},
+	}
+
+	for _, tt := range tests {
+		if got := registry.Supports(tt.url); got != tt.want {
+			t.Errorf("Supports(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package githost
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitHub talks to the GitHub REST API, or anything speaking the same dialect
+type GitHub struct {
+	Host   string // Repository host handled by this provider, e.g. "github.com"
+	client client
+}
+
+func NewGitHub(host, apiURL, token string, httpClient *http.Client) *GitHub {
+	return &GitHub{
+		Host: host,
+		client: client{
+			baseURL:    apiURL,
+			httpClient: httpClientOrDefault(httpClient),
+			authorize: func(req *http.Request) {
+				req.Header.Set("Accept", "application/vnd.github+json")
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+			},
+		},
+	}
+}
+
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+func (g *GitHub) Matches(host string) bool {
+	return host == g.Host
+}
+
+func (g *GitHub) Fetch(ctx context.Context, path string) (*Repository, error) {
+	path = githubRepoPath(path)
+
+	var repo struct {
+		FullName        string   `json:"full_name"`
+		Description     string   `json:"description"`
+		HTMLURL         string   `json:"html_url"`
+		Homepage        string   `json:"homepage"`
+		Topics          []string `json:"topics"`
+		StargazersCount int      `json:"stargazers_count"`
+		DefaultBranch   string   `json:"default_branch"`
+	}
+	if err := g.client.getJSON(ctx, "/repos/"+path, &repo); err != nil {
+		return nil, err
+	}
+
+	var languages map[string]int64
+	if err := g.client.getJSON(ctx, "/repos/"+path+"/languages", &languages); err != nil {
+		return nil, err
+	}
+
+	var commits []struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	if err := g.client.getJSON(ctx, "/repos/"+path+"/commits?per_page=1&sha="+repo.DefaultBranch, &commits); ignoreEmpty(err) != nil {
+		return nil, err
+	}
+
+	result := &Repository{
+		Provider:    g.Name(),
+		FullName:    repo.FullName,
+		Description: repo.Description,
+		WebURL:      repo.HTMLURL,
+		Homepage:    repo.Homepage,
+		Languages:   rankLanguages(languages),
+		Topics:      repo.Topics,
+		Stars:       repo.StargazersCount,
+	}
+	if len(commits) > 0 {
+		result.LastCommitAt = commits[0].Commit.Committer.Date
+	}
+	return result, nil
+}
+
+// githubRepoPath keeps owner/name of a path that goes on into the repository,
+// such as owner/name/tree/main taken from a browser URL
+func githubRepoPath(path string) string {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 2 {
+		return path
+	}
+	return parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+}
+
+// rankLanguages orders languages by their share of the code base, largest first
+func rankLanguages[T int64 | float64](languages map[string]T) []string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if languages[names[i]] != languages[names[j]] {
+			return languages[names[i]] > languages[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,103 @@
+package githost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newGitHubStandIn serves the GitHub endpoints the provider reads for owner/repo;
+// commitsStatus replaces the commit list when it is not 200
+func newGitHubStandIn(t *testing.T, commitsStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{"full_name":"owner/repo","description":"A repo","html_url":"https://github.com/owner/repo",
+			"homepage":"https://example.com","topics":["go"],"stargazers_count":42,"default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /repos/owner/repo/languages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Shell":100,"Go":5000,"Makefile":100}`))
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("sha"); got != "main" {
+			t.Errorf("sha = %q, want the default branch", got)
+		}
+		if commitsStatus != http.StatusOK {
+			w.WriteHeader(commitsStatus)
+			return
+		}
+		w.Write([]byte(`[{"commit":{"committer":{"date":"2024-05-01T10:00:00Z"}}}]`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHubFetch(t *testing.T) {
+	server := newGitHubStandIn(t, http.StatusOK)
+	github := NewGitHub("github.com", server.URL, "secret", nil)
+
+	repo, err := github.Fetch(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	want := &Repository{
+		Provider:     "github",
+		FullName:     "owner/repo",
+		Description:  "A repo",
+		WebURL:       "https://github.com/owner/repo",
+		Homepage:     "https://example.com",
+		Languages:    []string{"Go", "Makefile", "Shell"},
+		Topics:       []string{"go"},
+		Stars:        42,
+		LastCommitAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("Fetch = %+v, want %+v", repo, want)
+	}
+}
+
+func TestGitHubFetchBrowserURL(t *testing.T) {
+	server := newGitHubStandIn(t, http.StatusOK)
+	registry := NewRegistry(NewGitHub("github.com", server.URL, "secret", nil))
+
+	repo, err := registry.Fetch(context.Background(), "https://github.com/owner/repo/tree/main/docs")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if repo.FullName != "owner/repo" {
+		t.Errorf("FullName = %q, want owner/repo", repo.FullName)
+	}
+}
+
+func TestGitHubFetchNotFound(t *testing.T) {
+	server := newGitHubStandIn(t, http.StatusOK)
+	github := NewGitHub("github.com", server.URL, "secret", nil)
+
+	_, err := github.Fetch(context.Background(), "owner/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGitHubFetchEmptyRepository(t *testing.T) {
+	server := newGitHubStandIn(t, http.StatusConflict)
+	github := NewGitHub("github.com", server.URL, "secret", nil)
+
+	repo, err := github.Fetch(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !repo.LastCommitAt.IsZero() {
+		t.Errorf("LastCommitAt = %v, want zero for an empty repository", repo.LastCommitAt)
+	}
+}
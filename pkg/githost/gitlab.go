@@ -0,0 +1,87 @@
+package githost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLab talks to the GitLab REST API (v4)
+type GitLab struct {
+	Host   string // Repository host handled by this provider, e.g. "gitlab.com"
+	client client
+}
+
+func NewGitLab(host, apiURL, token string, httpClient *http.Client) *GitLab {
+	return &GitLab{
+		Host: host,
+		client: client{
+			baseURL:    apiURL,
+			httpClient: httpClientOrDefault(httpClient),
+			authorize: func(req *http.Request) {
+				if token != "" {
+					req.Header.Set("PRIVATE-TOKEN", token)
+				}
+			},
+		},
+	}
+}
+
+func (g *GitLab) Name() string {
+	return "gitlab"
+}
+
+func (g *GitLab) Matches(host string) bool {
+	return host == g.Host
+}
+
+func (g *GitLab) Fetch(ctx context.Context, path string) (*Repository, error) {
+	// Projects can sit in nested groups, so the project path only ends where
+	// GitLab's own pages start, as in group/sub/project/-/tree/main
+	if before, _, found := strings.Cut(path+"/", "/-/"); found {
+		path = before
+	}
+	project := "/projects/" + url.PathEscape(path)
+
+	var repo struct {
+		PathWithNamespace string   `json:"path_with_namespace"`
+		Description       string   `json:"description"`
+		WebURL            string   `json:"web_url"`
+		Topics            []string `json:"topics"`
+		StarCount         int      `json:"star_count"`
+		DefaultBranch     string   `json:"default_branch"`
+	}
+	if err := g.client.getJSON(ctx, project, &repo); err != nil {
+		return nil, err
+	}
+
+	var languages map[string]float64
+	if err := g.client.getJSON(ctx, project+"/languages", &languages); err != nil {
+		return nil, err
+	}
+
+	var commits []struct {
+		CommittedDate time.Time `json:"committed_date"`
+	}
+	commitsPath := fmt.Sprintf("%s/repository/commits?per_page=1&ref_name=%s", project, url.QueryEscape(repo.DefaultBranch))
+	if err := g.client.getJSON(ctx, commitsPath, &commits); ignoreEmpty(err) != nil {
+		return nil, err
+	}
+
+	result := &Repository{
+		Provider:    g.Name(),
+		FullName:    repo.PathWithNamespace,
+		Description: repo.Description,
+		WebURL:      repo.WebURL,
+		Languages:   rankLanguages(languages),
+		Topics:      repo.Topics,
+		Stars:       repo.StarCount,
+	}
+	if len(commits) > 0 {
+		result.LastCommitAt = commits[0].CommittedDate
+	}
+	return result, nil
+}
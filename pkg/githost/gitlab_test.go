@@ -0,0 +1,108 @@
+package githost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newGitLabStandIn serves the GitLab endpoints the provider reads for
+// group/sub/project; commitsStatus replaces the commit list when it is not 200
+func newGitLabStandIn(t *testing.T, commitsStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Fsub%2Fproject":
+			w.Write([]byte(`{"path_with_namespace":"group/sub/project","description":"A project",
+				"web_url":"https://gitlab.com/group/sub/project","topics":["rust"],"star_count":7,"default_branch":"trunk"}`))
+		case "/projects/group%2Fsub%2Fproject/languages":
+			w.Write([]byte(`{"Rust":80.5,"Shell":19.5}`))
+		case "/projects/group%2Fsub%2Fproject/repository/commits":
+			if got := r.URL.Query().Get("ref_name"); got != "trunk" {
+				t.Errorf("ref_name = %q, want the default branch", got)
+			}
+			if commitsStatus != http.StatusOK {
+				w.WriteHeader(commitsStatus)
+				return
+			}
+			w.Write([]byte(`[{"committed_date":"2024-06-02T08:30:00Z"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitLabFetch(t *testing.T) {
+	server := newGitLabStandIn(t, http.StatusOK)
+	gitlab := NewGitLab("gitlab.com", server.URL, "secret", nil)
+
+	repo, err := gitlab.Fetch(context.Background(), "group/sub/project")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	want := &Repository{
+		Provider:     "gitlab",
+		FullName:     "group/sub/project",
+		Description:  "A project",
+		WebURL:       "https://gitlab.com/group/sub/project",
+		Languages:    []string{"Rust", "Shell"},
+		Topics:       []string{"rust"},
+		Stars:        7,
+		LastCommitAt: time.Date(2024, 6, 2, 8, 30, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("Fetch = %+v, want %+v", repo, want)
+	}
+}
+
+func TestGitLabFetchBrowserURL(t *testing.T) {
+	server := newGitLabStandIn(t, http.StatusOK)
+	registry := NewRegistry(NewGitLab("gitlab.com", server.URL, "secret", nil))
+
+	repo, err := registry.Fetch(context.Background(), "https://gitlab.com/group/sub/project/-/tree/trunk")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if repo.FullName != "group/sub/project" {
+		t.Errorf("FullName = %q, want group/sub/project", repo.FullName)
+	}
+}
+
+func TestGitLabFetchNotFound(t *testing.T) {
+	server := newGitLabStandIn(t, http.StatusOK)
+	gitlab := NewGitLab("gitlab.com", server.URL, "secret", nil)
+
+	_, err := gitlab.Fetch(context.Background(), "group/missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGitLabFetchEmptyRepository(t *testing.T) {
+	for _, status := range []int{http.StatusConflict, http.StatusNotFound} {
+		server := newGitLabStandIn(t, status)
+		gitlab := NewGitLab("gitlab.com", server.URL, "secret", nil)
+
+		repo, err := gitlab.Fetch(context.Background(), "group/sub/project")
+		if err != nil {
+			t.Fatalf("Fetch with commits answering %d: %v", status, err)
+		}
+		if !repo.LastCommitAt.IsZero() {
+			t.Errorf("LastCommitAt = %v with commits answering %d, want zero", repo.LastCommitAt, status)
+		}
+	}
+}
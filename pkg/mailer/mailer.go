@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Message is one email to a single recipient. Text is required; HTML is sent
+// as an alternative part when set.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // Extra headers such as List-Unsubscribe
+}
+
+// Mailer delivers messages; implementations must be safe for concurrent use
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer only logs messages, for development and installations without mail
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.Info("mail", "to", msg.To, "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
@@ -0,0 +1,193 @@
+package socialcard
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of the card, the aspect ratio Open Graph and Twitter recommend
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin        = 80
+	titleSize     = 68
+	titleLines    = 3
+	tagSize       = 32
+	siteSize      = 34
+	accentWidth   = 16
+	maxTagsOnCard = 5
+)
+
+var (
+	backgroundTop    = color.RGBA{0x0f, 0x17, 0x2a, 0xff}
+	backgroundBottom = color.RGBA{0x1e, 0x29, 0x3b, 0xff}
+	accentColor      = color.RGBA{0x38, 0xbd, 0xf8, 0xff}
+	titleColor       = color.RGBA{0xf8, 0xfa, 0xfc, 0xff}
+	siteColor        = color.RGBA{0x94, 0xa3, 0xb8, 0xff}
+)
+
+// Card is the text shown on a social card
+type Card struct {
+	Title    string
+	Tags     []string
+	SiteName string
+}
+
+type faces struct {
+	title, tag, site font.Face
+}
+
+var (
+	loadFaces sync.Once
+	loaded    faces
+	loadErr   error
+)
+
+// Render draws card as a PNG. Titles too long for three lines are cut off with an ellipsis.
+func Render(card Card) ([]byte, error) {
+	loadFaces.Do(func() { loaded, loadErr = newFaces() })
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	for y := 0; y < Height; y++ {
+		draw.Draw(img, image.Rect(0, y, Width, y+1), image.NewUniform(blend(backgroundTop, backgroundBottom, float64(y)/Height)), image.Point{}, draw.Src)
+	}
+	draw.Draw(img, image.Rect(0, 0, accentWidth, Height), image.NewUniform(accentColor), image.Point{}, draw.Src)
+
+	lines := wrap(loaded.title, strings.TrimSpace(card.Title), Width-2*margin, titleLines)
+	lineHeight := loaded.title.Metrics().Height.Ceil()
+	y := margin + loaded.title.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		drawText(img, loaded.title, titleColor, margin, y, line)
+		y += lineHeight
+	}
+
+	if tags := tagLine(card.Tags); tags != "" {
+		tags = truncate(loaded.tag, tags, Width-2*margin)
+		drawText(img, loaded.tag, accentColor, margin, y+tagSize, tags)
+	}
+
+	if site := strings.TrimSpace(card.SiteName); site != "" {
+		site = truncate(loaded.site, site, Width-2*margin)
+		drawText(img, loaded.site, siteColor, margin, Height-margin, site)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newFaces() (faces, error) {
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return faces{}, err
+	}
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return faces{}, err
+	}
+
+	var f faces
+	if f.title, err = opentype.NewFace(bold, &opentype.FaceOptions{Size: titleSize, DPI: 72, Hinting: font.HintingFull}); err != nil {
+		return faces{}, err
+	}
+	if f.tag, err = opentype.NewFace(regular, &opentype.FaceOptions{Size: tagSize, DPI: 72, Hinting: font.HintingFull}); err != nil {
+		return faces{}, err
+	}
+	if f.site, err = opentype.NewFace(bold, &opentype.FaceOptions{Size: siteSize, DPI: 72, Hinting: font.HintingFull}); err != nil {
+		return faces{}, err
+	}
+	return f, nil
+}
+
+// wrap breaks text into at most maxLines lines no wider than width
+func wrap(face font.Face, text string, width, maxLines int) []string {
+	var lines []string
+	var current string
+
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current == "" || fits(face, candidate, width) {
+			current = candidate
+			continue
+		}
+
+		lines = append(lines, current)
+		if len(lines) == maxLines {
+			// Out of room: end the last line with what it can still hold
+			rest := strings.Join(words[i:], " ")
+			lines[maxLines-1] = truncate(face, lines[maxLines-1]+" "+rest, width)
+			return lines
+		}
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, truncate(face, current, width))
+	}
+	return lines
+}
+
+// truncate shortens text with an ellipsis until it fits width
+func truncate(face font.Face, text string, width int) string {
+	if fits(face, text, width) {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ") + "…"
+		if fits(face, candidate, width) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func fits(face font.Face, text string, width int) bool {
+	return font.MeasureString(face, text).Ceil() <= width
+}
+
+func tagLine(tags []string) string {
+	var parts []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			parts = append(parts, "#"+strings.ReplaceAll(tag, " ", ""))
+		}
+		if len(parts) == maxTagsOnCard {
+			break
+		}
+	}
+	return strings.Join(parts, "   ")
+}
+
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(text)
+}
+
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*t) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+// runSeed creates the first admin account when there is none yet and, with
+// --demo, a sample blog post and project for fresh environments
+func runSeed(cfg config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	username := flags.String("username", envOrDefault("SEED_ADMIN_USERNAME", "admin"), "admin login name")
+	email := flags.String("email", os.Getenv("SEED_ADMIN_EMAIL"), "admin email address")
+	password := flags.String("password", os.Getenv("SEED_ADMIN_PASSWORD"), "admin password, generated when empty")
+	demo := flags.Bool("demo", false, "also create demo content")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	users := service.NewUserService(repository.NewUserRepository(db))
+	admins, err := users.CountAdmins(ctx)
+	if err != nil {
+		return err
+	}
+
+	if admins > 0 {
+		fmt.Println("an admin account already exists, skipping")
+	} else {
+		if *email == "" {
+			return fmt.Errorf("--email (or SEED_ADMIN_EMAIL) is required to create the first admin")
+		}
+		plain, generated, err := passwordOrGenerate(*password)
+		if err != nil {
+			return err
+		}
+		user, err := users.CreateUser(ctx, *username, *email, plain, model.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		printUser("created", user, plain, generated)
+	}
+
+	if !*demo {
+		return nil
+	}
+
+	bus := newEventBus(db)
+	jobs, err := newJobQueue(db)
+	if err != nil {
+		return err
+	}
+
+	blogService := service.NewBlogService(repository.NewBlogRepository(db), repository.NewSeriesRepository(db), bus, jobs)
+	existing, err := blogService.GetBlogBySlug(ctx, "hello-world")
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		post := &model.BlogPost{
+			Title:     "Hello, world",
+			Slug:      "hello-world",
+			Summary:   "The first post on this site.",
+			Content:   "This post was created by the seed command.",
+			Published: true,
+			PublishAt: time.Now(),
+		}
+		if err := blogService.CreateBlog(ctx, post); err != nil {
+			return err
+		}
+		fmt.Println("created demo blog post", post.Slug)
+	}
+
+	portfolioRepo := repository.NewPortfolioRepository(db)
+	portfolioService := service.NewPortfolioService(portfolioRepo, repository.NewProjectTypeRepository(db), bus)
+	projects, err := portfolioService.GetAllProjects(ctx, model.ProjectFilter{}, 1, 1)
+	if err != nil {
+		return err
+	}
+	if projects.TotalRecord == 0 {
+		project := &model.PortfolioProject{
+			Title:        "This website",
+			Description:  "The API behind this personal website.",
+			ProjectType:  model.ProjectTypeCoding,
+			Technologies: []string{"Go", "PostgreSQL"},
+			StartDate:    time.Now(),
+		}
+		if err := portfolioService.CreateProject(ctx, project); err != nil {
+			return err
+		}
+		fmt.Println("created demo portfolio project", project.Title)
+	}
+
+	return nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
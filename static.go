@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+// runStaticExport implements the static-export subcommand, the CLI twin of
+// POST /api/admin/static-export
+func runStaticExport(cfg config.Config, args []string) error {
+	siteConfig, err := config.LoadSiteConfig()
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("static-export", flag.ContinueOnError)
+	flags.StringVar(&siteConfig.StaticExportDir, "out", siteConfig.StaticExportDir, "directory to write")
+	full := flags.Bool("full", false, "rewrite every file instead of only the changed ones")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: static-export [--out dir] [--full]")
+	}
+
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return err
+	}
+
+	bus := newEventBus(db)
+	jobs, err := newJobQueue(db)
+	if err != nil {
+		return err
+	}
+
+	seriesRepo := repository.NewSeriesRepository(db)
+	exporter := service.NewStaticExportService(
+		service.NewBlogService(repository.NewBlogRepository(db), seriesRepo, bus, jobs),
+		service.NewSeriesService(seriesRepo),
+		service.NewPortfolioService(repository.NewPortfolioRepository(db), repository.NewProjectTypeRepository(db), bus),
+		service.NewProjectTypeService(repository.NewProjectTypeRepository(db)),
+		service.NewTechnologyService(repository.NewTechnologyRepository(db)),
+		siteConfig,
+	)
+
+	report, err := exporter.Generate(context.Background(), *full)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
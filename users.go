@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+
+	"github.com/lutestringamend/perwebbe/internal/config"
+	"github.com/lutestringamend/perwebbe/internal/model"
+	"github.com/lutestringamend/perwebbe/internal/repository"
+	"github.com/lutestringamend/perwebbe/internal/service"
+)
+
+const userUsage = `usage: user <subcommand>
+
+subcommands:
+  create --username <name> --email <email> [--role admin|user] [--password <password>]
+  set-role <username|email> <admin|user>
+  activate <username|email>
+  deactivate <username|email>
+  reset-password <username|email> [--password <password>]
+
+--role defaults to user; when --password is omitted a random password is
+generated and printed once`
+
+// runUser implements the user subcommand
+func runUser(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(userUsage)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+		username := flags.String("username", "", "login name")
+		email := flags.String("email", "", "email address used to log in")
+		role := flags.String("role", model.RoleUser, "admin or user")
+		password := flags.String("password", "", "password, generated when empty")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		users, err := newUserService(cfg)
+		if err != nil {
+			return err
+		}
+		plain, generated, err := passwordOrGenerate(*password)
+		if err != nil {
+			return err
+		}
+		user, err := users.CreateUser(ctx, *username, *email, plain, *role)
+		if err != nil {
+			return err
+		}
+		printUser("created", user, plain, generated)
+		return nil
+
+	case "set-role":
+		if len(args) != 3 {
+			return fmt.Errorf(userUsage)
+		}
+		users, err := newUserService(cfg)
+		if err != nil {
+			return err
+		}
+		user, err := users.SetRole(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		printUser("updated", user, "", false)
+		return nil
+
+	case "activate", "deactivate":
+		if len(args) != 2 {
+			return fmt.Errorf(userUsage)
+		}
+		users, err := newUserService(cfg)
+		if err != nil {
+			return err
+		}
+		user, err := users.SetActive(ctx, args[1], args[0] == "activate")
+		if err != nil {
+			return err
+		}
+		printUser(args[0]+"d", user, "", false)
+		return nil
+
+	case "reset-password":
+		if len(args) < 2 {
+			return fmt.Errorf(userUsage)
+		}
+		flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+		password := flags.String("password", "", "new password, generated when empty")
+		if err := flags.Parse(args[2:]); err != nil {
+			return err
+		}
+
+		users, err := newUserService(cfg)
+		if err != nil {
+			return err
+		}
+		plain, generated, err := passwordOrGenerate(*password)
+		if err != nil {
+			return err
+		}
+		user, err := users.ResetPassword(ctx, args[1], plain)
+		if err != nil {
+			return err
+		}
+		printUser("reset password of", user, plain, generated)
+		return nil
+	}
+
+	return fmt.Errorf(userUsage)
+}
+
+func newUserService(cfg config.Config) (service.UserService, error) {
+	db, err := config.SetupDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewUserService(repository.NewUserRepository(db)), nil
+}
+
+func passwordOrGenerate(password string) (string, bool, error) {
+	if password != "" {
+		return password, false, nil
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), true, nil
+}
+
+func printUser(action string, user *model.User, password string, generated bool) {
+	fmt.Printf("%s user %q <%s> (id %d, role %s, active %t)\n", action, user.Username, user.Email, user.ID, user.Role, user.Active)
+	if generated {
+		fmt.Printf("generated password: %s\n", password)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/lutestringamend/perwebbe/internal/model"
+)
+
+// Set at build time, e.g.
+// go build -ldflags "-X main.version=1.4.0 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%FT%TZ)"
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
+var startedAt = time.Now()
+
+// versionInfo describes this build, taking the commit and its time from the
+// VCS stamp of the Go toolchain when they were not set at build time
+func versionInfo() model.VersionInfo {
+	info := model.VersionInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		StartedAt: startedAt.UTC(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			if setting.Value == "true" && commit == "" && info.Commit != "" {
+				info.Commit += "-dirty"
+			}
+		}
+	}
+	return info
+}